@@ -0,0 +1,180 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// TorrentFile is a torrent file to be added with AddTorrentFiles.
+type TorrentFile struct {
+	FileName          string
+	FileContentBase64 string
+	Options           *Options
+}
+
+// AddTorrentResult is the outcome of adding one of the files passed to AddTorrentFiles.
+type AddTorrentResult struct {
+	FileName string
+	// Hash is the torrent hash, set also when the torrent could not be added
+	// if the file content was valid
+	Hash string
+	Err  error
+}
+
+// ErrTorrentNotAdded is returned in AddTorrentResult when the daemon did not add
+// a torrent without reporting a specific error.
+var ErrTorrentNotAdded = errors.New("torrent was not added")
+
+// AddTorrentFileAsync adds a torrent via a base64 encoded file and returns the torrent hash;
+// unlike AddTorrentFile the daemon is not blocked while the torrent is being added.
+func (c *ClientV2) AddTorrentFileAsync(fileName, fileContentBase64 string, options *Options) (string, error) {
+	var args rencode.List
+	args.Add(fileName, fileContentBase64, options.toDictionary(c.v2daemon))
+
+	resp, err := c.rpc("core.add_torrent_file_async", args, rencode.Dictionary{})
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", resp.RPCError
+	}
+
+	// returned hash will be nil if torrent was already added
+	vals := resp.returnValue.Values()
+	if len(vals) == 0 {
+		return "", ErrInvalidReturnValue
+	}
+	torrentHash := vals[0]
+	if torrentHash == nil {
+		return "", nil
+	}
+	return string(torrentHash.([]uint8)), nil
+}
+
+// AddTorrentFiles adds many torrent files and returns a result for each of them, in the same order.
+// On v2 daemons all files are sent with a single call and the outcome is verified against the
+// session state; on v1 daemons the files are added one by one.
+// The returned error is set only when the operation could not be completed at all.
+func (c *Client) AddTorrentFiles(files []TorrentFile) ([]AddTorrentResult, error) {
+	results := make([]AddTorrentResult, len(files))
+	if !c.v2daemon {
+		for i, f := range files {
+			results[i].FileName = f.FileName
+			results[i].Hash, results[i].Err = c.AddTorrentFile(f.FileName, f.FileContentBase64, f.Options)
+			if results[i].Err == nil && results[i].Hash == "" {
+				// the torrent was already added
+				results[i].Err = ErrTorrentNotAdded
+				if metainfo, err := base64.StdEncoding.DecodeString(f.FileContentBase64); err == nil {
+					results[i].Hash, _ = torrentInfoHash(metainfo)
+				}
+			}
+
+			var rpcErr RPCError
+			if results[i].Err != nil && results[i].Err != ErrTorrentNotAdded && !errors.As(results[i].Err, &rpcErr) {
+				return nil, results[i].Err
+			}
+		}
+		return results, nil
+	}
+
+	var torrentFiles rencode.List
+	for i, f := range files {
+		results[i].FileName = f.FileName
+
+		metainfo, err := base64.StdEncoding.DecodeString(f.FileContentBase64)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Hash, results[i].Err = torrentInfoHash(metainfo)
+		if results[i].Err != nil {
+			continue
+		}
+
+		torrentFiles.Add(rencode.NewList(f.FileName, f.FileContentBase64, f.Options.toDictionary(c.v2daemon)))
+	}
+	if torrentFiles.Length() == 0 {
+		return results, nil
+	}
+
+	var args rencode.List
+	args.Add(torrentFiles)
+
+	resp, err := c.rpc("core.add_torrent_files", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, resp.RPCError
+	}
+
+	// the daemon returns a list of errors which are not associated with the files,
+	// match them through the torrent hash mentioned in the error message
+	var errorsList rencode.List
+	err = resp.returnValue.Scan(&errorsList)
+	if err != nil {
+		return nil, err
+	}
+	var unmatched []string
+	for _, v := range errorsList.Values() {
+		var msg string
+		if b, ok := v.([]byte); ok {
+			msg = string(b)
+		} else {
+			msg = fmt.Sprint(v)
+		}
+
+		matched := false
+		for i := range results {
+			if results[i].Err == nil && results[i].Hash != "" && strings.Contains(strings.ToLower(msg), results[i].Hash) {
+				results[i].Err = errors.New(msg)
+				matched = true
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, msg)
+		}
+	}
+
+	session, err := c.SessionState()
+	if err != nil {
+		return nil, err
+	}
+	inSession := make(map[string]struct{}, len(session))
+	for _, id := range session {
+		inSession[id] = struct{}{}
+	}
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		if _, ok := inSession[results[i].Hash]; ok {
+			continue
+		}
+		if len(unmatched) != 0 {
+			results[i].Err = fmt.Errorf("%w: %s", ErrTorrentNotAdded, strings.Join(unmatched, "; "))
+		} else {
+			results[i].Err = ErrTorrentNotAdded
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,95 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestAddTorrentFiles(t *testing.T) {
+	t.Parallel()
+
+	hash, err := torrentInfoHash([]byte(testTorrentMetainfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherMetainfo := "d4:infod6:lengthi1e4:name1:xee"
+	otherHash, err := torrentInfoHash([]byte(otherMetainfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := []TorrentFile{
+		{FileName: "test.torrent", FileContentBase64: base64.StdEncoding.EncodeToString([]byte(testTorrentMetainfo))},
+		{FileName: "other.torrent", FileContentBase64: base64.StdEncoding.EncodeToString([]byte(otherMetainfo))},
+		{FileName: "invalid.torrent", FileContentBase64: base64.StdEncoding.EncodeToString([]byte("garbage"))},
+	}
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("Torrent already in session ("+otherHash+")."))
+	conn.addResponse(2, rencode.NewList(hash, otherHash))
+
+	results, err := c.AddTorrentFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Hash != hash {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].Hash != otherHash {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+	if !errors.Is(results[2].Err, ErrInvalidBencode) {
+		t.Errorf("unexpected result %+v", results[2])
+	}
+
+	var torrentFiles rencode.List
+	method, args, _ := conn.requestAt(0)
+	err = args.Scan(&torrentFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "core.add_torrent_files" || torrentFiles.Length() != 2 {
+		t.Errorf("unexpected request %s%v", method, args.Values())
+	}
+}
+
+func TestAddTorrentFilesV1(t *testing.T) {
+	t.Parallel()
+
+	files := []TorrentFile{
+		{FileName: "a.torrent", FileContentBase64: "a"},
+		{FileName: "b.torrent", FileContentBase64: "b"},
+	}
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, "hash-a")
+	conn.addError(2, "AddTorrentError", "invalid torrent")
+
+	results, err := c.AddTorrentFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Hash != "hash-a" || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
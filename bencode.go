@@ -0,0 +1,139 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidBencode is returned when torrent metainfo cannot be decoded.
+var ErrInvalidBencode = errors.New("invalid bencoded data")
+
+// bdecode decodes the bencoded value at the start of data and returns it together with
+// the remaining bytes; values are decoded as int64, []byte, []interface{} and
+// map[string]interface{}.
+func bdecode(data []byte) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, ErrInvalidBencode
+	}
+
+	switch data[0] {
+	case 'i':
+		end := bytes.IndexByte(data, 'e')
+		if end < 0 {
+			return nil, nil, ErrInvalidBencode
+		}
+		n, err := strconv.ParseInt(string(data[1:end]), 10, 64)
+		if err != nil {
+			return nil, nil, ErrInvalidBencode
+		}
+		return n, data[end+1:], nil
+	case 'l':
+		list := []interface{}{}
+		rest := data[1:]
+		for len(rest) != 0 && rest[0] != 'e' {
+			var (
+				v   interface{}
+				err error
+			)
+			v, rest, err = bdecode(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			list = append(list, v)
+		}
+		if len(rest) == 0 {
+			return nil, nil, ErrInvalidBencode
+		}
+		return list, rest[1:], nil
+	case 'd':
+		dict := map[string]interface{}{}
+		rest := data[1:]
+		for len(rest) != 0 && rest[0] != 'e' {
+			var (
+				k, v interface{}
+				err  error
+			)
+			k, rest, err = bdecode(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			key, ok := k.([]byte)
+			if !ok {
+				return nil, nil, ErrInvalidBencode
+			}
+			v, rest, err = bdecode(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			dict[string(key)] = v
+		}
+		if len(rest) == 0 {
+			return nil, nil, ErrInvalidBencode
+		}
+		return dict, rest[1:], nil
+	}
+
+	// byte string, prefixed by its length
+	sep := bytes.IndexByte(data, ':')
+	if sep < 0 {
+		return nil, nil, ErrInvalidBencode
+	}
+	l, err := strconv.Atoi(string(data[:sep]))
+	if err != nil || l < 0 || len(data)-sep-1 < l {
+		return nil, nil, ErrInvalidBencode
+	}
+	return data[sep+1 : sep+1+l], data[sep+1+l:], nil
+}
+
+// torrentInfoHash returns the hex-encoded v1 info hash of the torrent metainfo,
+// which is the torrent ID used by Deluge.
+func torrentInfoHash(metainfo []byte) (string, error) {
+	if len(metainfo) == 0 || metainfo[0] != 'd' {
+		return "", ErrInvalidBencode
+	}
+
+	rest := metainfo[1:]
+	for len(rest) != 0 && rest[0] != 'e' {
+		var (
+			k   interface{}
+			err error
+		)
+		k, rest, err = bdecode(rest)
+		if err != nil {
+			return "", err
+		}
+		key, ok := k.([]byte)
+		if !ok {
+			return "", ErrInvalidBencode
+		}
+
+		value := rest
+		_, rest, err = bdecode(rest)
+		if err != nil {
+			return "", err
+		}
+		if string(key) == "info" {
+			sum := sha1.Sum(value[:len(value)-len(rest)])
+			return hex.EncodeToString(sum[:]), nil
+		}
+	}
+
+	return "", ErrInvalidBencode
+}
@@ -0,0 +1,66 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+const (
+	testTorrentInfo     = "d6:lengthi1024e4:name8:test.iso12:piece lengthi16384e6:pieces20:AAAAAAAAAAAAAAAAAAAAe"
+	testTorrentMetainfo = "d8:announce23:http://tracker/announce7:comment4:test4:info" + testTorrentInfo + "e"
+)
+
+func TestBdecode(t *testing.T) {
+	t.Parallel()
+
+	v, rest, err := bdecode([]byte("d1:ai-3e1:bl1:xi7eee!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"a": int64(-3),
+		"b": []interface{}{[]byte("x"), int64(7)},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+	if string(rest) != "!" {
+		t.Errorf("unexpected remaining bytes %q", rest)
+	}
+
+	for _, invalid := range []string{"", "i12", "l1:a", "5:abc", "di1e1:ae"} {
+		_, _, err = bdecode([]byte(invalid))
+		if err != ErrInvalidBencode {
+			t.Errorf("expected error for %q, got %v", invalid, err)
+		}
+	}
+}
+
+func TestTorrentInfoHash(t *testing.T) {
+	t.Parallel()
+
+	hash, err := torrentInfoHash([]byte(testTorrentMetainfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum([]byte(testTorrentInfo))
+	if expected := hex.EncodeToString(sum[:]); hash != expected {
+		t.Errorf("expected hash %s, got %s", expected, hash)
+	}
+}
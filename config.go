@@ -0,0 +1,241 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// EncryptionPolicy is the policy for encrypted incoming or outgoing connections.
+type EncryptionPolicy int64
+
+// The encryption policies, as defined in
+// https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/preferencesmanager.py#L305-L320
+const (
+	EncryptionForced   EncryptionPolicy = 0
+	EncryptionEnabled  EncryptionPolicy = 1
+	EncryptionDisabled EncryptionPolicy = 2
+)
+
+// EncryptionLevel is the level of encryption of connections.
+type EncryptionLevel int64
+
+const (
+	EncryptionLevelHandshake  EncryptionLevel = 0
+	EncryptionLevelFullStream EncryptionLevel = 1
+	EncryptionLevelEither     EncryptionLevel = 2
+)
+
+// CoreConfig contains the core configuration of the daemon, as stored in core.conf.
+// Nil fields are not reported by the daemon when retrieved and are left unchanged when set.
+// Valid keys for v2: https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/preferencesmanager.py#L39-L128
+// Valid keys for v1: https://github.com/deluge-torrent/deluge/blob/1.3-stable/deluge/core/preferencesmanager.py#L49-L149
+// The proxy settings are available via GetProxy.
+type CoreConfig struct {
+	// paths
+	DownloadLocation     *string
+	MoveCompleted        *bool
+	MoveCompletedPath    *string
+	CopyTorrentFile      *bool
+	DelCopyTorrentFile   *bool
+	TorrentfilesLocation *string
+	PluginsLocation      *string
+	GeoipDbLocation      *string
+
+	// network
+	DaemonPort          *int64
+	AllowRemote         *bool
+	ListenPorts         []int64 // first and last port of the range
+	ListenInterface     *string
+	OutgoingInterface   *string `rencode:"v2only"`
+	RandomPort          *bool
+	OutgoingPorts       []int64 // first and last port of the range
+	RandomOutgoingPorts *bool
+	Dht                 *bool
+	Upnp                *bool
+	Natpmp              *bool
+	Utpex               *bool
+	Lsd                 *bool
+	PeerTos             *string
+	RateLimitIpOverhead *bool
+
+	// encryption
+	EncInPolicy  *EncryptionPolicy
+	EncOutPolicy *EncryptionPolicy
+	EncLevel     *EncryptionLevel
+
+	// bandwidth, -1 is unlimited; speeds are in KiB/s
+	MaxConnectionsGlobal       *int64
+	MaxUploadSpeed             *float64
+	MaxDownloadSpeed           *float64
+	MaxUploadSlotsGlobal       *int64
+	MaxHalfOpenConnections     *int64
+	MaxConnectionsPerSecond    *int64
+	IgnoreLimitsOnLocalNetwork *bool
+	MaxConnectionsPerTorrent   *int64
+	MaxUploadSlotsPerTorrent   *int64
+	MaxUploadSpeedPerTorrent   *float64
+	MaxDownloadSpeedPerTorrent *float64
+
+	// queue, -1 is unlimited
+	MaxActiveSeeding      *int64
+	MaxActiveDownloading  *int64
+	MaxActiveLimit        *int64
+	DontCountSlowTorrents *bool
+	QueueNewToTop         *bool
+	AutoManagePreferSeeds *bool `rencode:"v2only"`
+	StopSeedAtRatio       *bool
+	RemoveSeedAtRatio     *bool
+	StopSeedRatio         *float64
+	ShareRatioLimit       *float64
+	SeedTimeRatioLimit    *float64
+	SeedTimeLimit         *int64 // in minutes
+
+	// defaults for new torrents
+	AddPaused                 *bool
+	AutoManaged               *bool
+	PrioritizeFirstLastPieces *bool
+	PreAllocateStorage        *bool `rencode:"v2only"`
+	SequentialDownload        *bool `rencode:"v2only"`
+	SuperSeeding              *bool `rencode:"v2only"`
+	Shared                    *bool `rencode:"v2only"`
+
+	// cache
+	CacheSize   *int64 // in 16 KiB blocks
+	CacheExpiry *int64 // in seconds
+
+	NewReleaseCheck *bool
+}
+
+// GetConfig returns the core configuration of the daemon.
+func (c *Client) GetConfig() (*CoreConfig, error) {
+	rd, err := c.rpcWithDictionaryResult("core.get_config", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	d, err := rd.Zip()
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg CoreConfig
+	err = decodeStruct(d, &cfg, c.excludeTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// GetConfigValue returns the value of a single core configuration key, as decoded from rencode
+// (strings are returned as byte slices).
+func (c *Client) GetConfigValue(key string) (interface{}, error) {
+	var args rencode.List
+	args.Add(key)
+
+	resp, err := c.rpc("core.get_config_value", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, resp.RPCError
+	}
+
+	if resp.returnValue.Length() != 1 {
+		return nil, fmt.Errorf("expected 1 return value, got %d", resp.returnValue.Length())
+	}
+
+	return resp.returnValue.Values()[0], nil
+}
+
+// GetConfigValues returns the values of the specified core configuration keys, as decoded from rencode.
+func (c *Client) GetConfigValues(keys ...string) (map[string]interface{}, error) {
+	var args rencode.List
+	args.Add(sliceToRencodeList(keys))
+
+	rd, err := c.rpcWithDictionaryResult("core.get_config_values", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	return rd.Zip()
+}
+
+// SetConfig sets the non-nil fields of the core configuration.
+// An error is returned when a v2-only field is set and the daemon is v1.
+func (c *Client) SetConfig(cfg *CoreConfig) error {
+	config, err := cfg.toDictionary(c.excludeTag)
+	if err != nil {
+		return err
+	}
+
+	var args rencode.List
+	args.Add(config)
+
+	resp, err := c.rpc("core.set_config", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+func (cfg *CoreConfig) toDictionary(excludeTag string) (rencode.Dictionary, error) {
+	var dict rencode.Dictionary
+	if cfg == nil {
+		return dict, nil
+	}
+
+	v := reflect.ValueOf(*cfg)
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.IsNil() {
+			continue
+		}
+
+		name := rencode.ToSnakeCase(t.Field(i).Name)
+		if hasTag(t.Field(i), excludeTag) {
+			return dict, fmt.Errorf("config key %q is not supported by v1 daemons", name)
+		}
+
+		if f.Kind() == reflect.Slice {
+			var list rencode.List
+			for j := 0; j < f.Len(); j++ {
+				list.Add(f.Index(j).Int())
+			}
+			dict.Add(name, list)
+			continue
+		}
+
+		// typed values are converted to their underlying type for the encoder
+		e := f.Elem()
+		switch e.Kind() {
+		case reflect.Int64:
+			dict.Add(name, e.Int())
+		default:
+			dict.Add(name, e.Interface())
+		}
+	}
+
+	return dict, nil
+}
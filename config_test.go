@@ -0,0 +1,133 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestGetConfig(t *testing.T) {
+	t.Parallel()
+
+	var d rencode.Dictionary
+	d.Add("download_location", "/downloads")
+	d.Add("listen_ports", rencode.NewList(int16(6881), int16(6891)))
+	d.Add("enc_in_policy", int8(1))
+	d.Add("max_upload_speed", int8(-1))
+	d.Add("stop_seed_ratio", float32(2))
+	d.Add("outgoing_interface", "eth0")
+	d.Add("unknown_key", "ignored")
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, d)
+
+	cfg, err := c.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DownloadLocation == nil || *cfg.DownloadLocation != "/downloads" {
+		t.Errorf("unexpected download location %v", cfg.DownloadLocation)
+	}
+	if len(cfg.ListenPorts) != 2 || cfg.ListenPorts[0] != 6881 || cfg.ListenPorts[1] != 6891 {
+		t.Errorf("unexpected listen ports %v", cfg.ListenPorts)
+	}
+	if cfg.EncInPolicy == nil || *cfg.EncInPolicy != EncryptionEnabled {
+		t.Errorf("unexpected incoming encryption policy %v", cfg.EncInPolicy)
+	}
+	if cfg.MaxUploadSpeed == nil || *cfg.MaxUploadSpeed != -1 {
+		t.Errorf("unexpected max upload speed %v", cfg.MaxUploadSpeed)
+	}
+	if cfg.StopSeedRatio == nil || *cfg.StopSeedRatio != 2 {
+		t.Errorf("unexpected stop seed ratio %v", cfg.StopSeedRatio)
+	}
+	if cfg.OutgoingInterface != nil {
+		t.Error("expected v2-only outgoing interface to be ignored on v1")
+	}
+	if cfg.MaxActiveLimit != nil {
+		t.Error("expected missing key to leave field nil")
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, nil)
+
+	level := EncryptionLevelFullStream
+	speed := 512.0
+	seeding := int64(5)
+	err := c.SetConfig(&CoreConfig{
+		EncLevel:         &level,
+		MaxDownloadSpeed: &speed,
+		MaxActiveSeeding: &seeding,
+		OutgoingPorts:    []int64{0, 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	method, args, _ := conn.lastMethod()
+	if method != "core.set_config" {
+		t.Fatalf("unexpected method %q", method)
+	}
+	var config rencode.Dictionary
+	err = args.Scan(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := config.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 4 {
+		t.Errorf("expected 4 keys, got %v", m)
+	}
+	if m["enc_level"] != int8(1) || m["max_download_speed"] != 512.0 || m["max_active_seeding"] != int8(5) {
+		t.Errorf("unexpected config %v", m)
+	}
+}
+
+func TestSetConfigV2OnlyOnV1(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClient(false, 0)
+
+	enabled := true
+	err := c.SetConfig(&CoreConfig{SequentialDownload: &enabled})
+	if err == nil {
+		t.Fatal("expected error when setting v2-only key on v1")
+	}
+	if len(conn.sentRequests()) != 0 {
+		t.Error("expected no request to be sent")
+	}
+}
+
+func TestGetConfigValue(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, "/downloads")
+
+	v, err := c.GetConfigValue("download_location")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := v.([]byte); !ok || string(b) != "/downloads" {
+		t.Errorf("unexpected value %v", v)
+	}
+}
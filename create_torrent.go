@@ -0,0 +1,195 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// ErrCreatedTorrentNotFound is returned when a torrent created with CreateTorrent
+// did not appear in the session in time.
+var ErrCreatedTorrentNotFound = errors.New("created torrent not found in session")
+
+// createTorrentPollInterval is the interval used to poll the session state for a created torrent.
+const createTorrentPollInterval = 500 * time.Millisecond
+
+// DefaultCreateTorrentTimeout is the time CreateTorrent waits for the created torrent to appear in the
+// session when CreateTorrentOptions.Timeout is zero.
+const DefaultCreateTorrentTimeout = 10 * time.Minute
+
+// CreateTorrentOptions are the options used to create a torrent on the daemon host.
+type CreateTorrentOptions struct {
+	// Path is the file or directory on the daemon host to create the torrent for
+	Path string
+	// Trackers are the tracker URLs grouped by tier; the first one is used as primary tracker
+	Trackers [][]string
+	// PieceLength is the piece size in bytes, a power of two
+	PieceLength int64
+	Comment     string
+	// Target is the path on the daemon host where the .torrent file is written; when empty
+	// the file is written next to Path, unless AddToSession is set
+	Target    string
+	WebSeeds  []string
+	Private   bool
+	CreatedBy string
+	// AddToSession adds the created torrent to the session, seeding from Path
+	AddToSession bool
+	// Timeout is the maximum time to wait for the created torrent to appear in the session
+	// when AddToSession is set; hashing large content can take minutes. When zero,
+	// DefaultCreateTorrentTimeout is used.
+	Timeout time.Duration
+}
+
+func (o *CreateTorrentOptions) toList() rencode.List {
+	var primaryTracker string
+	var tiers rencode.List
+	for _, tier := range o.Trackers {
+		if primaryTracker == "" && len(tier) != 0 {
+			primaryTracker = tier[0]
+		}
+		tiers.Add(sliceToRencodeList(tier))
+	}
+
+	var args rencode.List
+	args.Add(o.Path, primaryTracker, o.PieceLength, optionalString(o.Comment), optionalString(o.Target))
+	if len(o.WebSeeds) == 0 {
+		args.Add(nil)
+	} else {
+		args.Add(sliceToRencodeList(o.WebSeeds))
+	}
+	args.Add(o.Private, optionalString(o.CreatedBy))
+	if tiers.Length() == 0 {
+		args.Add(nil)
+	} else {
+		args.Add(tiers)
+	}
+	args.Add(o.AddToSession)
+
+	return args
+}
+
+// optionalString returns nil for an empty string, so that the daemon uses its default value.
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// CreateTorrent creates a torrent for a file or directory on the daemon host.
+// The daemon creates the torrent in background; when AddToSession is set, the session
+// state is polled until the new torrent appears and its hash is returned, otherwise
+// an empty hash is returned.
+// The hash is found from the torrents added to the session since the call; since the daemon
+// only logs creation errors, ErrCreatedTorrentNotFound is returned if no torrent is added before
+// the timeout of the options.
+func (c *Client) CreateTorrent(options CreateTorrentOptions) (string, error) {
+	if options.PieceLength <= 0 || options.PieceLength&(options.PieceLength-1) != 0 {
+		return "", fmt.Errorf("invalid piece length %d, must be a power of two", options.PieceLength)
+	}
+
+	var before map[string]struct{}
+	if options.AddToSession {
+		session, err := c.SessionState()
+		if err != nil {
+			return "", err
+		}
+		before = make(map[string]struct{}, len(session))
+		for _, id := range session {
+			before[id] = struct{}{}
+		}
+	}
+
+	resp, err := c.rpc("core.create_torrent", options.toList(), rencode.Dictionary{})
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", resp.RPCError
+	}
+
+	if !options.AddToSession {
+		return "", nil
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCreateTorrentTimeout
+	}
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); {
+		time.Sleep(createTorrentPollInterval)
+
+		session, err := c.SessionState()
+		if err != nil {
+			return "", err
+		}
+		var added []string
+		for _, id := range session {
+			if _, ok := before[id]; !ok {
+				added = append(added, id)
+			}
+		}
+		switch len(added) {
+		case 0:
+			continue
+		case 1:
+			return added[0], nil
+		}
+
+		// other torrents were added meanwhile: the created one is named after the last element of its path
+		id, err := c.addedTorrentNamed(added, path.Base(path.Clean(options.Path)))
+		if err != nil || id != "" {
+			return id, err
+		}
+	}
+
+	return "", ErrCreatedTorrentNotFound
+}
+
+// addedTorrentNamed returns the ID of the only torrent among ids with the given name, or an empty ID
+// if there is none.
+func (c *Client) addedTorrentNamed(ids []string, name string) (string, error) {
+	var filterDict rencode.Dictionary
+	filterDict.Add("id", sliceToRencodeList(ids))
+	d, err := c.torrentsStatusDictionaries(filterDict, rencode.NewList("name"))
+	if err != nil {
+		return "", err
+	}
+
+	var found string
+	for id, v := range d {
+		var s struct {
+			Name string
+		}
+		err = v.ToStruct(&s, "")
+		if err != nil {
+			return "", err
+		}
+		if s.Name != name {
+			continue
+		}
+		if found != "" {
+			return "", fmt.Errorf("more than one torrent named %q was added", name)
+		}
+		found = id
+	}
+
+	return found, nil
+}
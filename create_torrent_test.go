@@ -0,0 +1,98 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestCreateTorrent(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("oldhash"))
+	conn.addResponse(2, nil)
+	conn.addResponse(3, rencode.NewList("oldhash", "newhash"))
+
+	hash, err := c.CreateTorrent(CreateTorrentOptions{
+		Path:         "/data/iso/",
+		Trackers:     [][]string{{"http://tracker/announce"}, {"http://backup/announce"}},
+		PieceLength:  1 << 20,
+		Private:      true,
+		AddToSession: true,
+		Timeout:      time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "newhash" {
+		t.Errorf("expected hash %q, got %q", "newhash", hash)
+	}
+
+	var (
+		pieceLength   int64
+		path, tracker string
+	)
+	method, args, _ := conn.requestAt(1)
+	err = args.Scan(&path, &tracker, &pieceLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "core.create_torrent" || args.Length() != 10 || tracker != "http://tracker/announce" || pieceLength != 1<<20 {
+		t.Errorf("unexpected request %s%v", method, args.Values())
+	}
+}
+
+func TestCreateTorrentOtherAdded(t *testing.T) {
+	t.Parallel()
+
+	var iso, other, status rencode.Dictionary
+	iso.Add("name", "iso")
+	other.Add("name", "iso.old")
+	status.Add("newhash", iso)
+	status.Add("otherhash", other)
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("oldhash"))
+	conn.addResponse(2, nil)
+	conn.addResponse(3, rencode.NewList("oldhash", "otherhash", "newhash"))
+	conn.addResponse(4, status)
+
+	hash, err := c.CreateTorrent(CreateTorrentOptions{Path: "/data/iso", PieceLength: 1 << 18, AddToSession: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "newhash" {
+		t.Errorf("expected hash %q, got %q", "newhash", hash)
+	}
+}
+
+func TestCreateTorrentPieceLength(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	for _, pieceLength := range []int64{0, -1, 3 << 10} {
+		_, err := c.CreateTorrent(CreateTorrentOptions{Path: "/data/iso", PieceLength: pieceLength})
+		if err == nil {
+			t.Errorf("expected error for piece length %d", pieceLength)
+		}
+	}
+	if n := len(conn.sentRequests()); n != 0 {
+		t.Errorf("expected no requests, got %d", n)
+	}
+}
@@ -17,10 +17,13 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net/netip"
 	"os"
+	"path/filepath"
 	"strings"
 
 	delugeclient "github.com/gdm85/go-libdeluge"
@@ -34,10 +37,20 @@ var (
 
 	addURI               string
 	listTorrents         bool
+	statusKeys           string
+	skipFiles            string
+	addTracker           string
+	removeTracker        string
+	filterState          string
+	filterLabel          string
+	filterTrackerHost    string
 	listAvailablePlugins bool
 	listEnabledPlugins   bool
 	enablePlugin         string
 	disablePlugin        string
+	uploadPlugin         string
+	installPlugin        string
+	rescanPlugins        bool
 	listAccounts         bool
 	torrentHash          string
 	setLabel             string
@@ -49,6 +62,10 @@ var (
 	free                 bool
 	testListenPort       bool
 	sessionStatus        bool
+	pauseSession         bool
+	resumeSession        bool
+	sessionPaused        bool
+	connectPeer          string
 
 	fs = flag.NewFlagSet("default", flag.ContinueOnError)
 )
@@ -71,12 +88,22 @@ func init() {
 
 	fs.BoolVar(&listTorrents, "e", false, "List all torrents")
 	fs.BoolVar(&listTorrents, "list", false, "List all torrents")
+	fs.StringVar(&addTracker, "add-tracker", "", "Add a tracker URL, in a new last tier, to the specified torrent")
+	fs.StringVar(&removeTracker, "remove-tracker", "", "Remove a tracker URL from the specified torrent")
+	fs.StringVar(&skipFiles, "skip-files", "", "Skip the files matching a glob pattern (e.g. '*.nfo') of the specified torrent")
+	fs.StringVar(&filterState, "filter-state", "", "Only list torrents in this state")
+	fs.StringVar(&filterLabel, "filter-label", "", "Only list torrents with this label, an empty value selects unlabeled torrents")
+	fs.StringVar(&filterTrackerHost, "filter-tracker-host", "", "Only list torrents with this tracker host")
+	fs.StringVar(&statusKeys, "status-keys", "", "Comma-separated list of status keys to retrieve when listing torrents")
 	fs.BoolVar(&listEnabledPlugins, "list-enabled-plugins", false, "List enabled plugins")
 	fs.BoolVar(&listEnabledPlugins, "P", false, "List enabled plugins")
 	fs.BoolVar(&listAvailablePlugins, "list-available-plugins", false, "List available plugins")
 	fs.BoolVar(&listAvailablePlugins, "A", false, "List available plugins")
 	fs.StringVar(&enablePlugin, "enable-plugin", "", "Enable a plugin")
 	fs.StringVar(&disablePlugin, "disable-plugin", "", "Disable a plugin")
+	fs.StringVar(&uploadPlugin, "upload-plugin", "", "Upload a plugin .egg file")
+	fs.StringVar(&installPlugin, "install-plugin", "", "Upload a plugin .egg file and enable it")
+	fs.BoolVar(&rescanPlugins, "rescan-plugins", false, "Rescan the plugin folders")
 
 	fs.StringVar(&torrentHash, "torrent", "", "Operate on specified torrent hash")
 	fs.StringVar(&torrentHash, "t", "", "Operate on specified torrent hash")
@@ -99,6 +126,10 @@ func init() {
 	fs.BoolVar(&listAccounts, "list-accounts", false, "List all known user accounts")
 	fs.BoolVar(&sessionStatus, "s", false, "Show session status")
 	fs.BoolVar(&sessionStatus, "session-status", false, "Show session status")
+	fs.BoolVar(&pauseSession, "pause-session", false, "Pause the whole session")
+	fs.BoolVar(&resumeSession, "resume-session", false, "Resume the whole session")
+	fs.BoolVar(&sessionPaused, "session-paused", false, "Show whether the whole session is paused")
+	fs.StringVar(&connectPeer, "connect-peer", "", "Connect torrent to a peer, specified as 'ip:port' or '[ipv6]:port'")
 }
 
 func main() {
@@ -227,6 +258,37 @@ func main() {
 		}
 	}
 
+	if uploadPlugin != "" {
+		err := deluge.UploadPluginFile(uploadPlugin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: upload plugin %s: %v\n", uploadPlugin, err)
+			os.Exit(5)
+		}
+	}
+
+	if rescanPlugins {
+		err := deluge.RescanPlugins()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: rescan plugins: %v\n", err)
+			os.Exit(5)
+		}
+	}
+
+	if installPlugin != "" {
+		f, err := os.Open(installPlugin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: install plugin %s: %v\n", installPlugin, err)
+			os.Exit(5)
+		}
+		name, err := deluge.InstallPlugin(filepath.Base(installPlugin), f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: install plugin %s: %v\n", installPlugin, err)
+			os.Exit(5)
+		}
+		fmt.Println("installed and enabled plugin:", name)
+	}
+
 	if setLabel != "" {
 		if torrentHash == "" {
 			fmt.Fprintf(os.Stderr, "ERROR: no torrent hash specified\n")
@@ -316,8 +378,67 @@ func main() {
 		}
 	}
 
+	if addTracker != "" || removeTracker != "" {
+		if torrentHash == "" {
+			fmt.Fprintf(os.Stderr, "ERROR: no torrent hash specified\n")
+			os.Exit(5)
+		}
+
+		var torrentErrors []delugeclient.TorrentError
+		var err error
+		if addTracker != "" {
+			var current map[string][]delugeclient.Tracker
+			current, err = deluge.GetTorrentsTrackers([]string{torrentHash})
+			if err == nil {
+				var tier int64
+				if trackers := current[torrentHash]; len(trackers) != 0 {
+					tier = trackers[len(trackers)-1].Tier + 1
+				}
+				torrentErrors, err = deluge.AddTorrentsTrackers([]string{torrentHash}, []delugeclient.Tracker{{URL: addTracker, Tier: tier}})
+			}
+		} else {
+			torrentErrors, err = deluge.RemoveTorrentsTrackers([]string{torrentHash}, []string{removeTracker})
+		}
+		if err == nil && len(torrentErrors) != 0 {
+			err = errors.New(torrentErrors[0].Message)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: updating trackers of torrent %q: %v\n", torrentHash, err)
+			os.Exit(5)
+		}
+	}
+
+	if skipFiles != "" {
+		if torrentHash == "" {
+			fmt.Fprintf(os.Stderr, "ERROR: no torrent hash specified\n")
+			os.Exit(5)
+		}
+		skipped, err := deluge.SetFilePrioritiesMatching(torrentHash, skipFiles, delugeclient.FilePrioritySkip)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: skipping files %q of torrent %q: %v\n", skipFiles, torrentHash, err)
+			os.Exit(5)
+		}
+		fmt.Println("skipped files:", len(skipped))
+	}
+
 	if listTorrents {
-		torrents, err := deluge.TorrentsStatus(delugeclient.StateUnspecified, nil)
+		var keys []delugeclient.StatusKey
+		if statusKeys != "" {
+			for _, k := range strings.Split(statusKeys, ",") {
+				keys = append(keys, delugeclient.StatusKey(k))
+			}
+		}
+		filter := delugeclient.Filter{
+			State:       delugeclient.TorrentState(filterState),
+			TrackerHost: filterTrackerHost,
+		}
+		// an explicitly empty label selects unlabeled torrents
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "filter-label" {
+				filter.Label = &filterLabel
+			}
+		})
+		torrents, err := deluge.FilterTorrentsStatus(&filter, keys...)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR: could not list all torrents: %v\n", err)
 			os.Exit(6)
@@ -361,4 +482,46 @@ func main() {
 		}
 		fmt.Printf("session status: %+v\n", status)
 	}
+
+	if pauseSession {
+		err := deluge.PauseSession()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: could not pause session: %v\n", err)
+			os.Exit(6)
+		}
+	}
+
+	if resumeSession {
+		err := deluge.ResumeSession()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: could not resume session: %v\n", err)
+			os.Exit(6)
+		}
+	}
+
+	if sessionPaused {
+		paused, err := deluge.IsSessionPaused()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: could not get session pause state: %v\n", err)
+			os.Exit(6)
+		}
+		fmt.Printf("session paused: %v\n", paused)
+	}
+
+	if connectPeer != "" {
+		if torrentHash == "" {
+			fmt.Fprintf(os.Stderr, "ERROR: no torrent hash specified\n")
+			os.Exit(5)
+		}
+		peer, err := netip.ParseAddrPort(connectPeer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: invalid peer address %q: %v\n", connectPeer, err)
+			os.Exit(2)
+		}
+		err = deluge.ConnectPeer(torrentHash, peer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: connecting torrent %q to peer %v: %v\n", torrentHash, peer, err)
+			os.Exit(6)
+		}
+	}
 }
@@ -17,6 +17,7 @@
 package delugeclient
 
 import (
+	"bufio"
 	"bytes"
 	"compress/zlib"
 	"crypto/tls"
@@ -27,6 +28,8 @@ import (
 	"log"
 	"math"
 	"net"
+	"net/netip"
+	"strconv"
 	"time"
 
 	"github.com/gdm85/go-rencode"
@@ -45,6 +48,39 @@ const (
 	AuthLevelDefault  AuthLevel = AuthLevelNormal
 )
 
+// authLevels are the auth levels sorted by their numeric value, as defined in
+// https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/authmanager.py#L39-L45
+var authLevels = []struct {
+	level AuthLevel
+	value int64
+}{
+	{AuthLevelNone, 0},
+	{AuthLevelReadonly, 1},
+	{AuthLevelNormal, 5},
+	{AuthLevelAdmin, 10},
+}
+
+// Value returns the numeric value used by Deluge for the auth level, or -1 if it is not known.
+func (a AuthLevel) Value() int64 {
+	for _, l := range authLevels {
+		if l.level == a {
+			return l.value
+		}
+	}
+	return -1
+}
+
+// authLevelFromValue returns the highest auth level granted by the numeric value.
+func authLevelFromValue(value int64) AuthLevel {
+	result := AuthLevelNone
+	for _, l := range authLevels {
+		if value >= l.value {
+			result = l.level
+		}
+	}
+	return result
+}
+
 const (
 	// DefaultReadWriteTimeout is the default timeout for I/O operations with the Deluge server.
 	DefaultReadWriteTimeout = time.Second * 30
@@ -57,6 +93,9 @@ var (
 	ErrInvalidDictionaryResponse = errors.New("expected dictionary as list response")
 	// ErrInvalidReturnValue is returned when the returned value received from server is invalid.
 	ErrInvalidReturnValue = errors.New("invalid return value")
+	// ErrConnectionBroken is returned when the stream with the server could not be resynchronised;
+	// Connect must be called again to establish a new connection.
+	ErrConnectionBroken = errors.New("connection is broken")
 )
 
 // DelugeClient is an interface for v1.3 and v2 Deluge servers.
@@ -65,30 +104,75 @@ type DelugeClient interface {
 	Close() error
 
 	DaemonLogin() error
+	AuthLevel() AuthLevel
 	MethodsList() ([]string, error)
 	DaemonVersion() (string, error)
+	DaemonAuthorizedCall(method string) (bool, error)
+	DaemonShutdown() error
 	GetFreeSpace(string) (int64, error)
+	GetPathSize(path string) (int64, error)
 	GetLibtorrentVersion() (string, error)
 	AddTorrentMagnet(magnetURI string, options *Options) (string, error)
 	AddTorrentURL(url string, options *Options) (string, error)
 	AddTorrentFile(fileName, fileContentBase64 string, options *Options) (string, error)
+	AddTorrentFiles(files []TorrentFile) ([]AddTorrentResult, error)
+	CreateTorrent(options CreateTorrentOptions) (string, error)
 	RemoveTorrents(ids []string, rmFiles bool) ([]TorrentError, error)
 	RemoveTorrent(id string, rmFiles bool) (bool, error)
 	PauseTorrents(ids ...string) error
 	ResumeTorrents(ids ...string) error
+	QueueTop(ids ...string) error
+	QueueUp(ids ...string) error
+	QueueDown(ids ...string) error
+	QueueBottom(ids ...string) error
+	ReorderQueue(ids []string) error
+	PauseSession() error
+	ResumeSession() error
 	TorrentsStatus(state TorrentState, ids []string) (map[string]*TorrentStatus, error)
+	TorrentStatusKeys(id string, keys ...StatusKey) (*TorrentStatus, error)
+	TorrentsStatusKeys(state TorrentState, ids []string, keys ...StatusKey) (map[string]*TorrentStatus, error)
+	FilterTorrentsStatus(filter *Filter, keys ...StatusKey) (map[string]*TorrentStatus, error)
+	NewStatusSyncer(filter *Filter, keys ...StatusKey) *StatusSyncer
+	GetFilePriorities(id string) ([]FilePriority, error)
+	GetFilePriority(id string, index int64) (FilePriority, error)
+	GetFilePrioritiesMatching(id string, pattern string) (map[string]FilePriority, error)
+	SetFilePriorities(id string, priorities []FilePriority) error
+	SetFilePriority(id string, index int64, priority FilePriority) error
+	SetFilePrioritiesMatching(id string, pattern string, priority FilePriority) ([]int64, error)
 	TorrentStatus(id string) (*TorrentStatus, error)
 	MoveStorage(torrentIDs []string, dest string) error
+	ConnectPeer(id string, peer netip.AddrPort) error
+	ConnectPeers(ids []string, peer netip.AddrPort) ([]TorrentError, error)
 	SetTorrentTracker(id, tracker string) error
+	GetTorrentsTrackers(ids []string) (map[string][]Tracker, error)
+	SetTorrentsTrackers(ids []string, trackers []Tracker) ([]TorrentError, error)
+	AddTorrentsTrackers(ids []string, trackers []Tracker) ([]TorrentError, error)
+	RemoveTorrentsTrackers(ids []string, urls []string) ([]TorrentError, error)
+	SetTorrentsTrackerTiers(ids []string, tiers map[string]int64) ([]TorrentError, error)
 	SetTorrentOptions(id string, options *Options) error
+	RenameFiles(id string, renames map[int64]string) error
+	RenameFolder(id, folder, newFolder string) error
+	BulkRename(id string, rules []RenameRule, dryRun bool) ([]RenameOperation, error)
 	SessionState() ([]string, error)
+	GetFilterTree(showZeroHits bool, hideCategories []string) (*FilterTree, error)
 	ForceReannounce(ids []string) error
+	ForceRecheck(ids []string) error
+	ForceRecheckAndWait(ids []string, pollInterval, timeout time.Duration, progress RecheckProgressFunc) (*RecheckResult, error)
 	GetAvailablePlugins() ([]string, error)
 	GetEnabledPlugins() ([]string, error)
 	EnablePlugin(name string) error
 	DisablePlugin(name string) error
+	UploadPlugin(fileName string, r io.Reader) error
+	UploadPluginFile(path string) error
+	RescanPlugins() error
+	InstallPlugin(fileName string, r io.Reader) (string, error)
+	GetConfig() (*CoreConfig, error)
+	GetConfigValue(key string) (interface{}, error)
+	GetConfigValues(keys ...string) (map[string]interface{}, error)
+	SetConfig(cfg *CoreConfig) error
 	TestListenPort() (bool, error)
 	GetListenPort() (uint16, error)
+	NetworkInfo() (*NetworkInfo, error)
 	GetSessionStatus() (*SessionStatus, error)
 }
 
@@ -100,12 +184,24 @@ type V2 interface {
 	CreateAccount(account Account) (bool, error)
 	RemoveAccount(username string) (bool, error)
 	UpdateAccount(account Account) (bool, error)
+	DaemonGetVersion() (string, error)
+	GetAuthLevelsMappings() (map[AuthLevel]int64, error)
+	IsSessionPaused() (bool, error)
+	GetCompletionPaths(text string, showHiddenFiles bool) ([]string, error)
+	Glob(pattern string) ([]string, error)
+	BrowseDirectory(path string, showHiddenFiles bool) (*RemoteDirectory, error)
+	GetExternalIP() (string, error)
+	GetProxy() (*Proxy, error)
+	AddTorrentFileAsync(fileName, fileContentBase64 string, options *Options) (string, error)
+	PrefetchMagnetMetadata(magnetURI string, timeout time.Duration) (*MagnetMetadata, error)
 }
 
 // Client is a Deluge RPC client.
 type Client struct {
 	settings   Settings
 	safeConn   io.ReadWriteCloser
+	reader     *bufio.Reader
+	broken     bool
 	serial     int64
 	classID    int64
 	v2daemon   bool
@@ -296,6 +392,10 @@ func (c *Client) Close() error {
 const Deluge2ProtocolVersion = 1
 
 func (c *Client) rpc(methodName string, args rencode.List, kwargs rencode.Dictionary) (*DelugeResponse, error) {
+	if c.broken {
+		return nil, ErrConnectionBroken
+	}
+
 	// generate serial
 	c.serial++
 	if c.serial == math.MaxInt64 {
@@ -335,7 +435,7 @@ func (c *Client) rpc(methodName string, args rencode.List, kwargs rencode.Dictio
 		binary.BigEndian.PutUint32(header[1:], uint32(l))
 		_, err = c.safeConn.Write(header[:])
 		if err != nil {
-			return nil, err
+			return nil, c.markBroken(err)
 		}
 		if c.settings.Logger != nil {
 			c.settings.Logger.Printf("V2 request header: %X", header[:])
@@ -343,24 +443,76 @@ func (c *Client) rpc(methodName string, args rencode.List, kwargs rencode.Dictio
 	}
 	n, err := io.Copy(c.safeConn, &reqBytes)
 	if err != nil {
-		return nil, err
+		return nil, c.markBroken(err)
 	}
 	if c.settings.Logger != nil {
 		c.settings.Logger.Printf("written %d bytes to RPC connection", n)
 	}
 	if int(n) != l {
-		return nil, fmt.Errorf("expected to write %d raw request bytes but written %d bytes instead", l, n)
+		return nil, c.markBroken(fmt.Errorf("expected to write %d raw request bytes but written %d bytes instead", l, n))
+	}
+
+	for {
+		resp, err := c.readResponse(c.serial)
+		if err != nil {
+			var sme SerialMismatchError
+			if errors.As(err, &sme) && serialBefore(sme.ReceivedID, sme.ExpectedID) {
+				// a response to a previous request (e.g. one that timed out) arrived late; discard it
+				if c.settings.Logger != nil {
+					c.settings.Logger.Printf("discarding stale response with serial %d", sme.ReceivedID)
+				}
+				continue
+			}
+			if errors.As(err, &sme) {
+				// a response to a request which was never sent: the stream cannot be trusted anymore
+				return nil, c.markBroken(err)
+			}
+			return nil, err
+		}
+		if c.settings.Logger != nil {
+			c.settings.Logger.Printf("RPC(%s) = %s\n", methodName, resp.String())
+		}
+		return resp, nil
+	}
+}
+
+// serialBefore returns true if the serial a was generated before b; serials wrap around
+// from math.MaxInt64-1 to 1, and a gap of more than half the range is assumed to be a wraparound.
+func serialBefore(a, b int64) bool {
+	d := b - a
+	if d > 0 {
+		return d < math.MaxInt64/2
+	}
+	return d < -math.MaxInt64/2
+}
+
+// readResponse reads a single complete message from the connection.
+// If the message framing is lost the connection is marked as broken;
+// a timeout which happens before any byte of the message is received
+// leaves the stream in a consistent state.
+func (c *Client) readResponse(expectedSerial int64) (*DelugeResponse, error) {
+	if c.reader == nil {
+		c.reader = bufio.NewReader(c.safeConn)
 	}
 
 	// setup a reader pipeline for the response: TCP -> openssl -> ZLib -> (header in V2) rencode -> {Python objects}
-	var src io.Reader = c.safeConn
+	mr := &messageReader{r: c.reader}
+	var src io.Reader = mr
 
 	// when debugging copy the source bytes as they are received
 	if c.settings.DebugServerResponses {
-		var copyOfResponseBytes bytes.Buffer
-		src = io.TeeReader(src, &copyOfResponseBytes)
+		mr.copy = new(bytes.Buffer)
 
-		c.DebugServerResponses = append(c.DebugServerResponses, &copyOfResponseBytes)
+		c.DebugServerResponses = append(c.DebugServerResponses, mr.copy)
+	}
+
+	readFailed := func(err error) error {
+		var ne net.Error
+		if mr.n == 0 && errors.As(err, &ne) && ne.Timeout() {
+			// nothing of this message was consumed, the stream is still in sync
+			return err
+		}
+		return c.markBroken(err)
 	}
 
 	if c.v2daemon {
@@ -368,16 +520,16 @@ func (c *Client) rpc(methodName string, args rencode.List, kwargs rencode.Dictio
 		// a zlib header could be automatically detected but it's pointless since we use a flag to identify V2 daemons
 		// (remote endpoint does not version handshakes)
 		var header [5]byte
-		_, err = c.safeConn.Read(header[:])
+		_, err := io.ReadFull(src, header[:])
 		if err != nil {
-			return nil, err
+			return nil, readFailed(err)
 		}
 		if c.settings.Logger != nil {
 			c.settings.Logger.Printf("V2 response header: %X", header[:])
 		}
 
 		if header[0] != Deluge2ProtocolVersion {
-			return nil, fmt.Errorf("found protocol version %d but expected %d", header[0], Deluge2ProtocolVersion)
+			return nil, c.markBroken(fmt.Errorf("found protocol version %d but expected %d", header[0], Deluge2ProtocolVersion))
 		}
 
 		// read all the advertised bytes at once
@@ -386,31 +538,82 @@ func (c *Client) rpc(methodName string, args rencode.List, kwargs rencode.Dictio
 
 		n, err := io.CopyN(&respBytes, src, int64(l))
 		if err != nil {
-			return nil, err
+			return nil, c.markBroken(err)
 		}
 
 		if n != int64(l) {
-			return nil, fmt.Errorf("expected %d bytes read but got %d", l, n)
+			return nil, c.markBroken(fmt.Errorf("expected %d bytes read but got %d", l, n))
 		}
 
-		src = &respBytes
+		// the whole message has been consumed, decoding errors past this point do not affect the stream
+		zr, err := zlib.NewReader(&respBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.handleRPCResponse(rencode.NewDecoder(zr), expectedSerial)
 	}
 
 	zr, err := zlib.NewReader(src)
 	if err != nil {
-		return nil, err
+		return nil, readFailed(err)
 	}
 
-	d := rencode.NewDecoder(zr)
+	resp, err := c.handleRPCResponse(rencode.NewDecoder(zr), expectedSerial)
 
-	resp, err := c.handleRPCResponse(d, c.serial)
-	if err != nil {
-		return nil, err
+	// on v1 the message boundary is the end of the zlib stream, consume it entirely
+	// (including the checksum) so that the next message can be read
+	_, drainErr := io.Copy(io.Discard, zr)
+	if drainErr != nil {
+		return nil, c.markBroken(drainErr)
+	}
+
+	return resp, err
+}
+
+// markBroken closes the connection after the message framing has been lost;
+// all further calls will fail with ErrConnectionBroken until Connect is called again.
+func (c *Client) markBroken(cause error) error {
+	c.broken = true
+	c.reader = nil
+	if c.safeConn != nil {
+		_ = c.safeConn.Close()
+		c.safeConn = nil
 	}
 	if c.settings.Logger != nil {
-		c.settings.Logger.Printf("RPC(%s) = %s\n", methodName, resp.String())
+		c.settings.Logger.Printf("connection marked as broken: %v", cause)
+	}
+
+	return fmt.Errorf("%w: %w", ErrConnectionBroken, cause)
+}
+
+// messageReader counts the bytes read through it and optionally keeps a copy of them.
+// It implements io.ByteReader so that the zlib reader does not read past the end of a message.
+type messageReader struct {
+	r    *bufio.Reader
+	n    int64
+	copy *bytes.Buffer
+}
+
+func (mr *messageReader) Read(p []byte) (int, error) {
+	n, err := mr.r.Read(p)
+	mr.n += int64(n)
+	if mr.copy != nil {
+		mr.copy.Write(p[:n])
+	}
+	return n, err
+}
+
+func (mr *messageReader) ReadByte() (byte, error) {
+	b, err := mr.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	mr.n++
+	if mr.copy != nil {
+		mr.copy.WriteByte(b)
 	}
-	return resp, nil
+	return b, nil
 }
 
 func (c *Client) handleRPCResponse(d *rencode.Decoder, expectedSerial int64) (*DelugeResponse, error) {
@@ -485,16 +688,20 @@ func (c *Client) handleRPCResponse(d *rencode.Decoder, expectedSerial int64) (*D
 
 // Connect performs connection to a Deluge daemon and logs in.
 func (c *Client) Connect() error {
+	// the port is joined with JoinHostPort so that IPv6 literal hostnames are bracketed
+	address := net.JoinHostPort(c.settings.Hostname, strconv.FormatUint(uint64(c.settings.Port), 10))
 	dialer := new(net.Dialer)
-	rawConn, err := dialer.Dial("tcp", fmt.Sprintf("%s:%d", c.settings.Hostname, c.settings.Port))
+	rawConn, err := dialer.Dial("tcp", address)
 	if err != nil {
 		return err
 	}
 
 	c.safeConn = newSafeConn(rawConn, c.settings.Hostname, c.settings.ReadWriteTimeout)
+	c.reader = bufio.NewReader(c.safeConn)
+	c.broken = false
 
 	if c.settings.Logger != nil {
-		c.settings.Logger.Printf("connected to %s:%d\n", c.settings.Hostname, c.settings.Port)
+		c.settings.Logger.Printf("connected to %s\n", address)
 	}
 
 	err = c.DaemonLogin()
@@ -583,7 +790,8 @@ func (c *Client) rpcWithDictionaryResult(methodName string, args rencode.List, k
 	return rd, nil
 }
 
-// DaemonVersion returns the running daemon version.
+// DaemonVersion returns the running daemon version, as reported by daemon.info;
+// it is available on both v1 and v2 daemons.
 func (c *Client) DaemonVersion() (string, error) {
 	resp, err := c.rpc("daemon.info", rencode.List{}, rencode.Dictionary{})
 	if err != nil {
@@ -601,3 +809,66 @@ func (c *Client) DaemonVersion() (string, error) {
 
 	return info, nil
 }
+
+// AuthLevel returns the auth level granted to the logged-in user.
+func (c *Client) AuthLevel() AuthLevel {
+	return authLevelFromValue(c.classID)
+}
+
+// DaemonShutdown stops the daemon; the authenticated user must have an
+// authLevel of ADMIN to succeed.
+func (c *Client) DaemonShutdown() error {
+	resp, err := c.rpc("daemon.shutdown", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// DaemonGetVersion returns the running daemon version, as reported by daemon.get_version.
+// Unlike daemon.info used by DaemonVersion, it requires the user to be logged in and
+// is only available on v2 daemons; both return the same version.
+func (c *ClientV2) DaemonGetVersion() (string, error) {
+	resp, err := c.rpc("daemon.get_version", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", resp.RPCError
+	}
+
+	var version string
+	err = resp.returnValue.Scan(&version)
+	if err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// DaemonAuthorizedCall returns true if the logged-in user is allowed to call the
+// specified RPC method, e.g. "core.remove_torrent".
+func (c *Client) DaemonAuthorizedCall(method string) (bool, error) {
+	var args rencode.List
+	args.Add(method)
+
+	resp, err := c.rpc("daemon.authorized_call", args, rencode.Dictionary{})
+	if err != nil {
+		return false, err
+	}
+	if resp.IsError() {
+		return false, resp.RPCError
+	}
+
+	var authorized bool
+	err = resp.returnValue.Scan(&authorized)
+	if err != nil {
+		return false, err
+	}
+
+	return authorized, nil
+}
@@ -17,8 +17,10 @@ package delugeclient
 import (
 	"bytes"
 	"compress/zlib"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"testing"
 )
 
@@ -52,3 +54,102 @@ func TestZlibEOF(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expected, s)
 	}
 }
+
+func TestStaleResponsesAreDiscarded(t *testing.T) {
+	t.Parallel()
+
+	for _, v2daemon := range []bool{false, true} {
+		c, conn := newMockConnClient(v2daemon, 4)
+		// late responses to requests which previously timed out
+		conn.addResponse(2, "stale")
+		conn.addError(3, "TimeoutError", "stale")
+		conn.addResponse(5, "2.0.3")
+
+		ver, err := c.DaemonVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ver != "2.0.3" {
+			t.Errorf("expected version %q but got %q", "2.0.3", ver)
+		}
+	}
+}
+
+func TestStaleResponsesAfterWraparound(t *testing.T) {
+	t.Parallel()
+
+	// the serial of the next request wraps around to 1
+	c, conn := newMockConnClient(true, math.MaxInt64-1)
+	conn.addResponse(math.MaxInt64-2, "stale")
+	conn.addResponse(1, "2.0.3")
+
+	ver, err := c.DaemonVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ver != "2.0.3" {
+		t.Errorf("expected version %q but got %q", "2.0.3", ver)
+	}
+}
+
+func TestSerialBefore(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		a, b     int64
+		expected bool
+	}{
+		{1, 2, true},
+		{2, 1, false},
+		{2, 2, false},
+		{math.MaxInt64 - 1, 1, true},
+		{1, math.MaxInt64 - 1, false},
+	} {
+		if serialBefore(tc.a, tc.b) != tc.expected {
+			t.Errorf("serialBefore(%d, %d) != %v", tc.a, tc.b, tc.expected)
+		}
+	}
+}
+
+func TestShortReads(t *testing.T) {
+	t.Parallel()
+
+	for _, v2daemon := range []bool{false, true} {
+		c, conn := newMockConnClient(v2daemon, 0)
+		conn.oneByteReads = true
+		conn.addResponse(1, "2.0.3")
+		conn.addResponse(2, "2.0.4")
+
+		for _, expected := range []string{"2.0.3", "2.0.4"} {
+			ver, err := c.DaemonVersion()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ver != expected {
+				t.Errorf("expected version %q but got %q", expected, ver)
+			}
+		}
+	}
+}
+
+func TestFutureSerialBreaksConnection(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClient(true, 0)
+	conn.addResponse(7, "2.0.3")
+	conn.addResponse(1, "2.0.3")
+
+	_, err := c.DaemonVersion()
+	if !errors.Is(err, ErrConnectionBroken) {
+		t.Fatalf("expected a broken connection error, got %v", err)
+	}
+	var sme SerialMismatchError
+	if !errors.As(err, &sme) {
+		t.Errorf("expected a serial mismatch error, got %v", err)
+	}
+
+	_, err = c.DaemonVersion()
+	if err != ErrConnectionBroken {
+		t.Errorf("expected %v, got %v", ErrConnectionBroken, err)
+	}
+}
@@ -0,0 +1,249 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// FilePriority is the download priority of a file of a torrent.
+// The values are the ones used by v2 daemons, where the values between the defined priorities
+// are valid as well; they are converted for v1 daemons.
+type FilePriority int64
+
+// The file priorities, see FILE_PRIORITY in
+// v2: https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/common.py
+// v1: https://github.com/deluge-torrent/deluge/blob/1.3-stable/deluge/common.py
+const (
+	FilePrioritySkip   FilePriority = 0
+	FilePriorityLow    FilePriority = 1 // same as FilePriorityNormal on v1, which has no low priority
+	FilePriorityNormal FilePriority = 4
+	FilePriorityHigh   FilePriority = 7
+)
+
+// String returns the name of the priority.
+func (p FilePriority) String() string {
+	switch p {
+	case FilePrioritySkip:
+		return "Skip"
+	case FilePriorityLow:
+		return "Low"
+	case FilePriorityNormal:
+		return "Normal"
+	case FilePriorityHigh:
+		return "High"
+	}
+	return fmt.Sprintf("FilePriority(%d)", int64(p))
+}
+
+// filePriorityToWire returns the value of the priority for the daemon.
+func filePriorityToWire(p FilePriority, v2daemon bool) int64 {
+	if v2daemon {
+		return int64(p)
+	}
+	// v1 scale: 0 do not download, 1 normal, 2 high, 5 highest
+	switch {
+	case p <= FilePrioritySkip:
+		return 0
+	case p < FilePriorityHigh:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// filePriorityFromWire returns the priority of a value received from the daemon; v2 values are
+// returned as they are, v1 values are mapped to the closest priority.
+func filePriorityFromWire(v int64, v2daemon bool) FilePriority {
+	if v2daemon {
+		return FilePriority(v)
+	}
+	switch {
+	case v <= 0:
+		return FilePrioritySkip
+	case v == 1:
+		return FilePriorityNormal
+	default:
+		return FilePriorityHigh
+	}
+}
+
+// matchFilePath returns true if the file path matches the glob pattern; patterns without
+// a separator are matched against the file name only, like "*.nfo".
+func matchFilePath(pattern, filePath string) (bool, error) {
+	if !strings.Contains(pattern, "/") {
+		filePath = path.Base(filePath)
+	}
+	return path.Match(pattern, filePath)
+}
+
+// GetFilePriorities returns the priority of each file of the torrent, in the order of the file indexes.
+func (c *Client) GetFilePriorities(id string) ([]FilePriority, error) {
+	ts, err := c.TorrentStatusKeys(id, StatusKeyFilePriorities)
+	if err != nil {
+		return nil, err
+	}
+	return ts.FilePriorities, nil
+}
+
+// GetFilePriority returns the priority of the file with the specified index.
+func (c *Client) GetFilePriority(id string, index int64) (FilePriority, error) {
+	priorities, err := c.GetFilePriorities(id)
+	if err != nil {
+		return 0, err
+	}
+	if index < 0 || index >= int64(len(priorities)) {
+		return 0, fmt.Errorf("file index %d out of range, torrent has %d files", index, len(priorities))
+	}
+	return priorities[index], nil
+}
+
+// GetFilePrioritiesMatching returns the priority of the files matching the glob pattern, indexed by path.
+// Patterns without a "/" are matched against the file names, otherwise against the full paths.
+func (c *Client) GetFilePrioritiesMatching(id string, pattern string) (map[string]FilePriority, error) {
+	fp, err := c.filesAndPriorities(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]FilePriority{}
+	for _, f := range fp.Files {
+		ok, err := matchFilePath(pattern, f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result[f.Path] = filePriorityFromWire(fp.FilePriorities[f.Index], c.v2daemon)
+		}
+	}
+
+	return result, nil
+}
+
+// filesPriorities contains the files of a torrent and their priorities as sent by the daemon.
+type filesPriorities struct {
+	Files          []File
+	FilePriorities []int64
+}
+
+// filesAndPriorities returns the files of the torrent and their priorities without conversion,
+// checking that there is a priority for each file index; the priorities of the files which are
+// not changed must be sent back as they are, since the conversion is not reversible.
+func (c *Client) filesAndPriorities(id string) (*filesPriorities, error) {
+	var args rencode.List
+	args.Add(id)
+	args.Add(c.statusKeys([]StatusKey{StatusKeyFiles, StatusKeyFilePriorities}))
+
+	rd, err := c.rpcWithDictionaryResult("core.get_torrent_status", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	d, err := rd.Zip()
+	if err != nil {
+		return nil, err
+	}
+
+	var fp filesPriorities
+	err = decodeStruct(d, &fp, c.excludeTag)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fp.Files {
+		if f.Index < 0 || f.Index >= int64(len(fp.FilePriorities)) {
+			return nil, fmt.Errorf("no priority for file %q with index %d", f.Path, f.Index)
+		}
+	}
+
+	return &fp, nil
+}
+
+// setWireFilePriorities sets the priorities of the files of the torrent, as values for the daemon.
+func (c *Client) setWireFilePriorities(id string, priorities []int64) error {
+	var list rencode.List
+	for _, p := range priorities {
+		list.Add(p)
+	}
+	var options rencode.Dictionary
+	options.Add("file_priorities", list)
+
+	var args rencode.List
+	args.Add(id, options)
+
+	resp, err := c.rpc("core.set_torrent_options", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// SetFilePriorities sets the priority of each file of the torrent, in the order of the file indexes.
+func (c *Client) SetFilePriorities(id string, priorities []FilePriority) error {
+	return c.SetTorrentOptions(id, &Options{FilePriorities: priorities})
+}
+
+// SetFilePriority sets the priority of the file with the specified index; the priorities of
+// the other files are left unchanged.
+func (c *Client) SetFilePriority(id string, index int64, priority FilePriority) error {
+	fp, err := c.filesAndPriorities(id)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= int64(len(fp.FilePriorities)) {
+		return fmt.Errorf("file index %d out of range, torrent has %d files", index, len(fp.FilePriorities))
+	}
+	fp.FilePriorities[index] = filePriorityToWire(priority, c.v2daemon)
+
+	return c.setWireFilePriorities(id, fp.FilePriorities)
+}
+
+// SetFilePrioritiesMatching sets the priority of the files matching the glob pattern and returns
+// their indexes; see GetFilePrioritiesMatching for the pattern syntax.
+// The priorities of the other files are left unchanged; nothing is changed when no file matches.
+func (c *Client) SetFilePrioritiesMatching(id string, pattern string, priority FilePriority) ([]int64, error) {
+	fp, err := c.filesAndPriorities(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []int64
+	for _, f := range fp.Files {
+		ok, err := matchFilePath(pattern, f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fp.FilePriorities[f.Index] = filePriorityToWire(priority, c.v2daemon)
+			matched = append(matched, f.Index)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	err = c.setWireFilePriorities(id, fp.FilePriorities)
+	if err != nil {
+		return nil, err
+	}
+
+	return matched, nil
+}
@@ -0,0 +1,139 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+var testPriorityFiles = []File{
+	{Index: 0, Size: 100, Offset: 0, Path: "Show/e01.mkv"},
+	{Index: 1, Size: 100, Offset: 100, Path: "Show/Sample/e01.mkv"},
+	{Index: 2, Size: 100, Offset: 200, Path: "Show/show.nfo"},
+}
+
+func TestSetFilePrioritiesMatching(t *testing.T) {
+	t.Parallel()
+
+	for _, v2daemon := range []bool{false, true} {
+		c, conn := newMockConnClient(v2daemon, 0)
+		if v2daemon {
+			conn.addResponse(1, testStatusDictionary("files", testPriorityFiles, "file_priorities", rencode.NewList(int8(4), int8(2), int8(4))))
+		} else {
+			// the highest priority of v1 has no equivalent and must be preserved
+			conn.addResponse(1, testStatusDictionary("files", testPriorityFiles, "file_priorities", rencode.NewList(int8(1), int8(5), int8(1))))
+		}
+		conn.addResponse(2, nil)
+
+		matched, err := c.SetFilePrioritiesMatching(testStatusHash, "*.nfo", FilePrioritySkip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(matched, []int64{2}) {
+			t.Errorf("unexpected matched files %v", matched)
+		}
+
+		method, args, _ := conn.lastMethod()
+		var (
+			id      string
+			options rencode.Dictionary
+		)
+		err = args.Scan(&id, &options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := options.Zip()
+		if err != nil {
+			t.Fatal(err)
+		}
+		priorities, ok := m["file_priorities"].(rencode.List)
+		if !ok {
+			t.Fatalf("expected key %q to be a list", "file_priorities")
+		}
+		expected := []interface{}{int8(4), int8(2), int8(0)}
+		if !v2daemon {
+			expected = []interface{}{int8(1), int8(5), int8(0)}
+		}
+		if method != "core.set_torrent_options" || !reflect.DeepEqual(priorities.Values(), expected) {
+			t.Errorf("v2 %v: unexpected request %s %v", v2daemon, method, priorities.Values())
+		}
+	}
+}
+
+func TestGetFilePrioritiesMatching(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary("files", testPriorityFiles, "file_priorities", rencode.NewList(int8(4), int8(0), int8(7))))
+
+	priorities, err := c.GetFilePrioritiesMatching(testStatusHash, "Show/Sample/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(priorities, map[string]FilePriority{"Show/Sample/e01.mkv": FilePrioritySkip}) {
+		t.Errorf("unexpected priorities %v", priorities)
+	}
+}
+
+func TestFilePriorityFromWire(t *testing.T) {
+	t.Parallel()
+
+	v1 := map[int64]FilePriority{0: FilePrioritySkip, 1: FilePriorityNormal, 2: FilePriorityHigh, 5: FilePriorityHigh}
+	for v, expected := range v1 {
+		if p := filePriorityFromWire(v, false); p != expected {
+			t.Errorf("v1 priority %d: expected %v, got %v", v, expected, p)
+		}
+	}
+	v2 := map[int64]FilePriority{0: FilePrioritySkip, 1: FilePriorityLow, 4: FilePriorityNormal, 5: 5, 7: FilePriorityHigh}
+	for v, expected := range v2 {
+		if p := filePriorityFromWire(v, true); p != expected {
+			t.Errorf("v2 priority %d: expected %v, got %v", v, expected, p)
+		}
+	}
+}
+
+func TestSetFilePriority(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary("files", testPriorityFiles, "file_priorities", rencode.NewList(int8(6), int8(3), int8(4))))
+	conn.addResponse(2, nil)
+
+	err := c.SetFilePriority(testStatusHash, 2, FilePriorityHigh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		id      string
+		options rencode.Dictionary
+	)
+	err = args.Scan(&id, &options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := options.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priorities, ok := m["file_priorities"].(rencode.List)
+	if !ok || !reflect.DeepEqual(priorities.Values(), []interface{}{int8(6), int8(3), int8(7)}) {
+		t.Errorf("unexpected options %v", m)
+	}
+}
@@ -0,0 +1,184 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// FileNode is a file or a directory of a torrent.
+type FileNode struct {
+	Name  string
+	Path  string // full path, with "/" separators; empty for the root
+	Index int64  // file index, -1 for directories
+	Size  int64  // for directories, the total size of the files they contain
+	Done  int64  // bytes downloaded, estimated from the file progress
+
+	// Priority is the priority of the file; for directories it is the highest priority
+	// of the files they contain and Mixed is true if the files have different priorities
+	Priority FilePriority
+	Mixed    bool
+
+	Children []*FileNode // sorted by name, nil for files
+}
+
+// IsDir returns true if the node is a directory.
+func (n *FileNode) IsDir() bool {
+	return n.Index == -1
+}
+
+// Progress returns the fraction of the node which has been downloaded, from 0 to 1.
+func (n *FileNode) Progress() float64 {
+	if n.Size == 0 {
+		return 0
+	}
+	return float64(n.Done) / float64(n.Size)
+}
+
+// FileTree is the hierarchy of the files of a torrent.
+type FileTree struct {
+	Root *FileNode
+}
+
+// FileTree returns the hierarchy of the files of the torrent; priorities and progress are zero
+// when the respective status fields were not retrieved.
+func (ts *TorrentStatus) FileTree() (*FileTree, error) {
+	return NewFileTree(ts.Files, ts.FilePriorities, ts.FileProgress)
+}
+
+// NewFileTree returns the hierarchy of files with the specified priorities and progress, both indexed
+// by file index; either can be empty.
+func NewFileTree(files []File, priorities []FilePriority, progress []float32) (*FileTree, error) {
+	if len(priorities) != 0 && len(priorities) != len(files) {
+		return nil, fmt.Errorf("expected %d file priorities, got %d", len(files), len(priorities))
+	}
+	if len(progress) != 0 && len(progress) != len(files) {
+		return nil, fmt.Errorf("expected %d file progress values, got %d", len(files), len(progress))
+	}
+
+	root := &FileNode{Index: -1}
+	dirs := map[string]*FileNode{"": root}
+	for _, f := range files {
+		if f.Index < 0 || f.Index >= int64(len(files)) {
+			return nil, fmt.Errorf("file %q: invalid index %d", f.Path, f.Index)
+		}
+
+		node := &FileNode{
+			Path:  f.Path,
+			Index: f.Index,
+			Size:  f.Size,
+		}
+		if len(priorities) != 0 {
+			node.Priority = priorities[f.Index]
+		}
+		if len(progress) != 0 {
+			node.Done = int64(float64(f.Size) * float64(progress[f.Index]))
+		}
+
+		parent := root
+		elems := strings.Split(f.Path, "/")
+		for i, name := range elems[:len(elems)-1] {
+			dirPath := strings.Join(elems[:i+1], "/")
+			dir, ok := dirs[dirPath]
+			if !ok {
+				dir = &FileNode{Name: name, Path: dirPath, Index: -1}
+				dirs[dirPath] = dir
+				parent.Children = append(parent.Children, dir)
+			}
+			parent = dir
+		}
+		node.Name = elems[len(elems)-1]
+		parent.Children = append(parent.Children, node)
+	}
+
+	root.aggregate()
+
+	return &FileTree{Root: root}, nil
+}
+
+// aggregate computes size, bytes done and priority of the directories and sorts their children.
+func (n *FileNode) aggregate() {
+	if !n.IsDir() {
+		return
+	}
+
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	n.Size, n.Done, n.Priority, n.Mixed = 0, 0, 0, false
+	for i, c := range n.Children {
+		c.aggregate()
+		n.Size += c.Size
+		n.Done += c.Done
+		if c.Mixed || (i > 0 && c.Priority != n.Priority) {
+			n.Mixed = true
+		}
+		if i == 0 || c.Priority > n.Priority {
+			n.Priority = c.Priority
+		}
+	}
+}
+
+// Lookup returns the node with the specified path, or nil if there is none.
+// The root has the empty path.
+func (t *FileTree) Lookup(path string) *FileNode {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return t.Root
+	}
+
+	n := t.Root
+	for _, name := range strings.Split(path, "/") {
+		i := sort.Search(len(n.Children), func(i int) bool { return n.Children[i].Name >= name })
+		if i == len(n.Children) || n.Children[i].Name != name {
+			return nil
+		}
+		n = n.Children[i]
+	}
+
+	return n
+}
+
+// Walk calls fn for each node of the tree in depth-first order, parents before children.
+// If fn returns fs.SkipDir for a directory its children are skipped; any other error stops the walk
+// and is returned.
+func (t *FileTree) Walk(fn func(n *FileNode) error) error {
+	err := walkFileNode(t.Root, fn)
+	if errors.Is(err, fs.SkipDir) {
+		return nil
+	}
+	return err
+}
+
+func walkFileNode(n *FileNode, fn func(n *FileNode) error) error {
+	err := fn(n)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range n.Children {
+		err = walkFileNode(c, fn)
+		if errors.Is(err, fs.SkipDir) && c.IsDir() {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
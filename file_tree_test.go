@@ -0,0 +1,103 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+func testFileTree(t *testing.T) *FileTree {
+	ts := TorrentStatus{
+		Files: []File{
+			{Index: 0, Size: 100, Path: "Show/S01/e01.mkv"},
+			{Index: 1, Size: 100, Path: "Show/S01/e02.mkv"},
+			{Index: 2, Size: 50, Path: "Show/S02/e01.mkv"},
+			{Index: 3, Size: 10, Path: "Show/show.nfo"},
+		},
+		FilePriorities: []FilePriority{FilePriorityNormal, FilePriorityNormal, FilePriorityLow, FilePrioritySkip},
+		FileProgress:   []float32{1, 0.5, 0, 0},
+	}
+
+	tree, err := ts.FileTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestFileTree(t *testing.T) {
+	t.Parallel()
+
+	tree := testFileTree(t)
+
+	show := tree.Lookup("Show")
+	if show == nil || !show.IsDir() || show.Size != 260 || show.Done != 150 {
+		t.Fatalf("unexpected show node %+v", show)
+	}
+	if show.Priority != 4 || !show.Mixed {
+		t.Errorf("unexpected show priority %d (mixed %v)", show.Priority, show.Mixed)
+	}
+
+	s01 := tree.Lookup("/Show/S01/")
+	if s01 == nil || s01.Progress() != 0.75 || s01.Mixed {
+		t.Errorf("unexpected season node %+v", s01)
+	}
+
+	nfo := tree.Lookup("Show/show.nfo")
+	if nfo == nil || nfo.IsDir() || nfo.Index != 3 || nfo.Name != "show.nfo" {
+		t.Errorf("unexpected file node %+v", nfo)
+	}
+
+	if tree.Lookup("Show/S03") != nil {
+		t.Error("expected missing node")
+	}
+	if tree.Lookup("") != tree.Root {
+		t.Error("expected root node")
+	}
+}
+
+func TestFileTreeWalk(t *testing.T) {
+	t.Parallel()
+
+	tree := testFileTree(t)
+
+	var paths []string
+	err := tree.Walk(func(n *FileNode) error {
+		paths = append(paths, n.Path)
+		if n.Path == "Show/S01" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"", "Show", "Show/S01", "Show/S02", "Show/S02/e01.mkv", "Show/show.nfo"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestFileTreeMismatch(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFileTree([]File{{Index: 0, Size: 1, Path: "a"}}, []FilePriority{1, 1}, nil)
+	if err == nil {
+		t.Error("expected error for mismatched priorities")
+	}
+}
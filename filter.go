@@ -0,0 +1,77 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"sort"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// Filter selects torrents on the daemon side; a torrent must match all the specified criteria.
+// Empty criteria are not used, a nil filter selects all torrents.
+// Label is a pointer so that a pointer to an empty string can select unlabeled torrents.
+// See https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/filtermanager.py#L129-L170
+type Filter struct {
+	State       TorrentState // StateActive selects torrents with transfer activity
+	IDs         []string
+	Label       *string // requires the Label plugin
+	TrackerHost string  // "Error" selects torrents with a tracker error
+	Owner       string
+
+	// Other contains additional keys, for example provided by plugins;
+	// values can be strings, []string to match any of several values,
+	// or any other type supported by rencode
+	Other map[string]interface{}
+}
+
+func (f *Filter) toDictionary() rencode.Dictionary {
+	var dict rencode.Dictionary
+	if f == nil {
+		return dict
+	}
+
+	if len(f.IDs) != 0 {
+		dict.Add("id", sliceToRencodeList(f.IDs))
+	}
+	if f.State != StateUnspecified {
+		dict.Add("state", string(f.State))
+	}
+	if f.Label != nil {
+		dict.Add("label", *f.Label)
+	}
+	if f.TrackerHost != "" {
+		dict.Add("tracker_host", f.TrackerHost)
+	}
+	if f.Owner != "" {
+		dict.Add("owner", f.Owner)
+	}
+
+	// sort the other keys for a deterministic request
+	keys := make([]string, 0, len(f.Other))
+	for k := range f.Other {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if values, ok := f.Other[k].([]string); ok {
+			dict.Add(k, sliceToRencodeList(values))
+			continue
+		}
+		dict.Add(k, f.Other[k])
+	}
+
+	return dict
+}
@@ -0,0 +1,96 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestFilterTorrentsStatus(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.Dictionary{})
+
+	label := "tv"
+	_, err := c.FilterTorrentsStatus(&Filter{
+		State:       StateSeeding,
+		Label:       &label,
+		TrackerHost: "tracker.example.org",
+		Other: map[string]interface{}{
+			"owner_group": "media",
+			"category":    []string{"movies", "series"},
+		},
+	}, StatusKeyName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		filter rencode.Dictionary
+		keys   rencode.List
+	)
+	err = args.Scan(&filter, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := filter.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"state":        []byte("Seeding"),
+		"label":        []byte("tv"),
+		"tracker_host": []byte("tracker.example.org"),
+		"owner_group":  []byte("media"),
+	}
+	category, ok := m["category"].(rencode.List)
+	if !ok || !reflect.DeepEqual(category.Values(), []interface{}{[]byte("movies"), []byte("series")}) {
+		t.Errorf("unexpected category filter %v", m["category"])
+	}
+	delete(m, "category")
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("unexpected filter %v", m)
+	}
+}
+
+func TestFilterUnlabeled(t *testing.T) {
+	t.Parallel()
+
+	label := ""
+	f := &Filter{Label: &label}
+	dict := f.toDictionary()
+	m, err := dict.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, map[string]interface{}{"label": ""}) {
+		t.Errorf("unexpected filter %v", m)
+	}
+}
+
+func TestNilFilter(t *testing.T) {
+	t.Parallel()
+
+	var f *Filter
+	dict := f.toDictionary()
+	if dict.Length() != 0 {
+		t.Errorf("expected empty filter, got %v", dict)
+	}
+}
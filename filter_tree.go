@@ -0,0 +1,100 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"github.com/gdm85/go-rencode"
+)
+
+// FilterCount is the number of torrents matching a value of a filter category.
+type FilterCount struct {
+	Value string
+	Count int64
+}
+
+// FilterTree contains the torrent counts for each value of the filter categories,
+// as displayed in the sidebar of the Deluge clients.
+type FilterTree struct {
+	State       []FilterCount
+	TrackerHost []FilterCount
+	// Label is only available when the Label plugin is enabled
+	Label []FilterCount
+	// Owner is only available on v2 daemons
+	Owner []FilterCount
+	// Other contains the categories registered by other plugins
+	Other map[string][]FilterCount
+}
+
+// GetFilterTree returns the torrent counts for each filter category; values without any
+// torrent are included only when showZeroHits is set, and the categories in hideCategories
+// are not returned.
+func (c *Client) GetFilterTree(showZeroHits bool, hideCategories []string) (*FilterTree, error) {
+	var args rencode.List
+	if len(hideCategories) == 0 {
+		args.Add(showZeroHits, nil)
+	} else {
+		args.Add(showZeroHits, sliceToRencodeList(hideCategories))
+	}
+
+	rd, err := c.rpcWithDictionaryResult("core.get_filter_tree", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := rd.Zip()
+	if err != nil {
+		return nil, err
+	}
+
+	var tree FilterTree
+	for category, v := range d {
+		values, ok := v.(rencode.List)
+		if !ok {
+			return nil, ErrInvalidReturnValue
+		}
+
+		counts := make([]FilterCount, 0, values.Length())
+		for _, rv := range values.Values() {
+			pair, ok := rv.(rencode.List)
+			if !ok {
+				return nil, ErrInvalidReturnValue
+			}
+			var fc FilterCount
+			err = pair.Scan(&fc.Value, &fc.Count)
+			if err != nil {
+				return nil, err
+			}
+			counts = append(counts, fc)
+		}
+
+		switch category {
+		case "state":
+			tree.State = counts
+		case "tracker_host":
+			tree.TrackerHost = counts
+		case "label":
+			tree.Label = counts
+		case "owner":
+			tree.Owner = counts
+		default:
+			if tree.Other == nil {
+				tree.Other = map[string][]FilterCount{}
+			}
+			tree.Other[category] = counts
+		}
+	}
+
+	return &tree, nil
+}
@@ -0,0 +1,64 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestGetFilterTree(t *testing.T) {
+	t.Parallel()
+
+	var tree rencode.Dictionary
+	tree.Add("state", rencode.NewList(rencode.NewList("All", 3), rencode.NewList("Seeding", 2), rencode.NewList("Paused", 1)))
+	tree.Add("tracker_host", rencode.NewList(rencode.NewList("All", 3), rencode.NewList("ubuntu.com", 3)))
+	tree.Add("owner", rencode.NewList(rencode.NewList("localclient", 3)))
+	tree.Add("custom", rencode.NewList())
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, tree)
+
+	ft, err := c.GetFilterTree(false, []string{"label"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedState := []FilterCount{{"All", 3}, {"Seeding", 2}, {"Paused", 1}}
+	if !reflect.DeepEqual(ft.State, expectedState) {
+		t.Errorf("expected state counts %v, got %v", expectedState, ft.State)
+	}
+	if len(ft.TrackerHost) != 2 || len(ft.Owner) != 1 || ft.Label != nil {
+		t.Errorf("unexpected filter tree %+v", ft)
+	}
+	if _, ok := ft.Other["custom"]; !ok {
+		t.Errorf("expected category %q in other categories", "custom")
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		showZeroHits bool
+		hideCat      rencode.List
+	)
+	err = args.Scan(&showZeroHits, &hideCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if showZeroHits || hideCat.Length() != 1 {
+		t.Errorf("unexpected arguments %v", args.Values())
+	}
+}
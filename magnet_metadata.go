@@ -0,0 +1,195 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// ErrMetadataNotFound is returned when the metadata of a magnet could not be retrieved before the timeout.
+var ErrMetadataNotFound = errors.New("magnet metadata not found")
+
+// ErrInvalidMetadataTimeout is returned when the metadata timeout is shorter than one second.
+var ErrInvalidMetadataTimeout = errors.New("magnet metadata timeout must be at least one second")
+
+// MetadataFile is a file described by torrent metadata.
+type MetadataFile struct {
+	Index int64
+	// Path is the file path, prefixed by the torrent name for multi-file torrents
+	Path string
+	Size int64
+}
+
+// MagnetMetadata is the torrent metadata retrieved for a magnet URI.
+type MagnetMetadata struct {
+	Hash        string
+	Name        string
+	TotalSize   int64
+	PieceLength int64
+	Private     bool
+	Files       []MetadataFile
+	// Metadata is the raw bencoded metadata, as received from the daemon
+	Metadata []byte
+}
+
+// PrefetchMagnetMetadata retrieves the metadata of a magnet URI without adding it to the session;
+// the daemon waits at most for timeout, which is rounded up to whole seconds and must be
+// at least one second.
+func (c *ClientV2) PrefetchMagnetMetadata(magnetURI string, timeout time.Duration) (*MagnetMetadata, error) {
+	if timeout < time.Second {
+		return nil, ErrInvalidMetadataTimeout
+	}
+	seconds := int64((timeout + time.Second - 1) / time.Second)
+	timeout = time.Duration(seconds) * time.Second
+
+	var args rencode.List
+	args.Add(magnetURI, seconds)
+
+	// the daemon replies only after the metadata is received or the timeout expires
+	if sc, ok := c.safeConn.(*safeConn); ok {
+		readWriteTimeout := sc.readWriteTimeout
+		sc.readWriteTimeout += timeout
+		defer func() {
+			sc.readWriteTimeout = readWriteTimeout
+		}()
+	}
+
+	resp, err := c.rpc("core.prefetch_magnet_metadata", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, resp.RPCError
+	}
+
+	// a tuple of the torrent hash and the base64 encoded metadata is returned
+	var (
+		result  rencode.List
+		hash    string
+		encoded []byte
+	)
+	err = resp.returnValue.Scan(&result)
+	if err != nil {
+		return nil, err
+	}
+	err = result.Scan(&hash, &encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) == 0 {
+		return nil, ErrMetadataNotFound
+	}
+
+	metadata, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parseMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	m.Hash = hash
+
+	return m, nil
+}
+
+// FilePriorities returns the file priorities to be used in Options when adding the torrent,
+// with the normal priority for the selected files and all other files skipped.
+func (m *MagnetMetadata) FilePriorities(selected func(f MetadataFile) bool) []FilePriority {
+	priorities := make([]FilePriority, len(m.Files))
+	for i, f := range m.Files {
+		if selected(f) {
+			priorities[i] = FilePriorityNormal
+		}
+	}
+	return priorities
+}
+
+// parseMetadata parses bencoded torrent metadata, either a whole metainfo or only its info dictionary.
+func parseMetadata(metadata []byte) (*MagnetMetadata, error) {
+	v, _, err := bdecode(metadata)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidBencode
+	}
+	if i, ok := info["info"].(map[string]interface{}); ok {
+		info = i
+	}
+
+	m := MagnetMetadata{
+		Name:     bencodeString(info, "name"),
+		Metadata: metadata,
+	}
+	m.PieceLength, _ = info["piece length"].(int64)
+	private, _ := info["private"].(int64)
+	m.Private = private == 1
+
+	if length, ok := info["length"].(int64); ok {
+		// single-file torrent
+		m.Files = []MetadataFile{{Path: m.Name, Size: length}}
+		m.TotalSize = length
+		return &m, nil
+	}
+
+	files, ok := info["files"].([]interface{})
+	if !ok {
+		return nil, ErrInvalidBencode
+	}
+	for i, rf := range files {
+		f, ok := rf.(map[string]interface{})
+		if !ok {
+			return nil, ErrInvalidBencode
+		}
+		elems, ok := f["path.utf-8"].([]interface{})
+		if !ok {
+			elems, ok = f["path"].([]interface{})
+			if !ok {
+				return nil, ErrInvalidBencode
+			}
+		}
+		path := []string{m.Name}
+		for _, e := range elems {
+			b, ok := e.([]byte)
+			if !ok {
+				return nil, ErrInvalidBencode
+			}
+			path = append(path, string(b))
+		}
+		size, _ := f["length"].(int64)
+
+		m.Files = append(m.Files, MetadataFile{Index: int64(i), Path: strings.Join(path, "/"), Size: size})
+		m.TotalSize += size
+	}
+
+	return &m, nil
+}
+
+// bencodeString returns the string value of a key, preferring its UTF-8 variant when available.
+func bencodeString(d map[string]interface{}, key string) string {
+	if b, ok := d[key+".utf-8"].([]byte); ok {
+		return string(b)
+	}
+	b, _ := d[key].([]byte)
+	return string(b)
+}
@@ -0,0 +1,101 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+const testMultiFileInfo = "d5:filesld6:lengthi700e4:pathl8:Season 18:ep01.mkveed6:lengthi10e4:pathl8:info.nfoeee4:name4:Show12:piece lengthi16384e6:pieces20:AAAAAAAAAAAAAAAAAAAA7:privatei1ee"
+
+func TestPrefetchMagnetMetadata(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("c1939ca413b9afcc34ea0cf3c128574e93ff6cb0", base64.StdEncoding.EncodeToString([]byte(testMultiFileInfo))))
+
+	m, err := c.PrefetchMagnetMetadata(testMagnetURI, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []MetadataFile{
+		{Index: 0, Path: "Show/Season 1/ep01.mkv", Size: 700},
+		{Index: 1, Path: "Show/info.nfo", Size: 10},
+	}
+	if !reflect.DeepEqual(m.Files, expected) {
+		t.Errorf("expected files %+v, got %+v", expected, m.Files)
+	}
+	if m.Name != "Show" || m.TotalSize != 710 || !m.Private || m.Hash != "c1939ca413b9afcc34ea0cf3c128574e93ff6cb0" {
+		t.Errorf("unexpected metadata %+v", m)
+	}
+
+	priorities := m.FilePriorities(func(f MetadataFile) bool {
+		return !strings.HasSuffix(f.Path, ".nfo")
+	})
+	if !reflect.DeepEqual(priorities, []FilePriority{FilePriorityNormal, FilePrioritySkip}) {
+		t.Errorf("unexpected file priorities %v", priorities)
+	}
+}
+
+func TestPrefetchMagnetMetadataTimeout(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("c1939ca413b9afcc34ea0cf3c128574e93ff6cb0", ""))
+
+	_, err := c.PrefetchMagnetMetadata(testMagnetURI, time.Second)
+	if err != ErrMetadataNotFound {
+		t.Errorf("expected %v, got %v", ErrMetadataNotFound, err)
+	}
+}
+
+func TestPrefetchMagnetMetadataTimeoutSeconds(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("c1939ca413b9afcc34ea0cf3c128574e93ff6cb0", ""))
+
+	_, err := c.PrefetchMagnetMetadata(testMagnetURI, 1500*time.Millisecond)
+	if err != ErrMetadataNotFound {
+		t.Errorf("expected %v, got %v", ErrMetadataNotFound, err)
+	}
+	var (
+		uri     string
+		seconds int64
+	)
+	_, args, _ := conn.lastMethod()
+	err = args.Scan(&uri, &seconds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seconds != 2 {
+		t.Errorf("expected timeout of 2 seconds, got %d", seconds)
+	}
+
+	_, err = c.PrefetchMagnetMetadata(testMagnetURI, 500*time.Millisecond)
+	if err != ErrInvalidMetadataTimeout {
+		t.Errorf("expected %v, got %v", ErrInvalidMetadataTimeout, err)
+	}
+	if len(conn.sentRequests()) != 1 {
+		t.Error("expected no request to be sent for an invalid timeout")
+	}
+}
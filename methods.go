@@ -15,7 +15,14 @@
 package delugeclient
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
 
 	"github.com/gdm85/go-rencode"
 )
@@ -270,6 +277,59 @@ func (c *Client) ResumeTorrents(ids ...string) error {
 	return err
 }
 
+// PauseSession pauses the whole session, stopping all transfers.
+func (c *Client) PauseSession() error {
+	method := "core.pause_session"
+	if !c.v2daemon {
+		method = "core.pause_all_torrents"
+	}
+	resp, err := c.rpc(method, rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// ResumeSession resumes the whole session after PauseSession.
+func (c *Client) ResumeSession() error {
+	method := "core.resume_session"
+	if !c.v2daemon {
+		method = "core.resume_all_torrents"
+	}
+	resp, err := c.rpc(method, rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// IsSessionPaused returns true if the whole session is paused.
+func (c *ClientV2) IsSessionPaused() (bool, error) {
+	resp, err := c.rpc("core.is_session_paused", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return false, err
+	}
+	if resp.IsError() {
+		return false, resp.RPCError
+	}
+
+	var paused bool
+	err = resp.returnValue.Scan(&paused)
+	if err != nil {
+		return false, err
+	}
+
+	return paused, nil
+}
+
 // MoveStorage will move the storage location of the group of torrents with the given IDs.
 func (c *Client) MoveStorage(torrentIDs []string, dest string) error {
 	var args rencode.List
@@ -286,17 +346,21 @@ func (c *Client) MoveStorage(torrentIDs []string, dest string) error {
 	return err
 }
 
-// SessionState returns the current session state.
-func (c *Client) SessionState() ([]string, error) {
-	return c.rpcWithStringsResult("core.get_session_state")
-}
+// ErrInvalidPeer is returned when the peer address or port is not set.
+var ErrInvalidPeer = errors.New("invalid peer address")
+
+// ConnectPeer makes the torrent with the given hash connect to the specified peer.
+// ErrInvalidPeer is returned without contacting the daemon if the peer has no
+// valid address or a zero port.
+func (c *Client) ConnectPeer(id string, peer netip.AddrPort) error {
+	if !peer.IsValid() || peer.Port() == 0 {
+		return ErrInvalidPeer
+	}
 
-// SetTorrentOptions updates options for the torrent with the given hash.
-func (c *Client) SetTorrentOptions(id string, options *Options) error {
 	var args rencode.List
-	args.Add(id, options.toDictionary(c.v2daemon))
+	args.Add(id, peer.Addr().Unmap().String(), int(peer.Port()))
 
-	resp, err := c.rpc("core.set_torrent_options", args, rencode.Dictionary{})
+	resp, err := c.rpc("core.connect_peer", args, rencode.Dictionary{})
 	if err != nil {
 		return err
 	}
@@ -307,20 +371,35 @@ func (c *Client) SetTorrentOptions(id string, options *Options) error {
 	return nil
 }
 
-// SetTorrentTracker sets the primary tracker for the torrent with the
-// given hash to be `trackerURL`.
-func (c *Client) SetTorrentTracker(id, trackerURL string) error {
-	var tracker rencode.Dictionary
-	tracker.Add("url", trackerURL)
-	tracker.Add("tier", 0)
+// ConnectPeers makes each of the torrents with the given IDs connect to the specified peer.
+// Torrents for which the daemon reported an error are returned as TorrentErrors.
+func (c *Client) ConnectPeers(ids []string, peer netip.AddrPort) ([]TorrentError, error) {
+	var torrentErrors []TorrentError
+	for _, id := range ids {
+		err := c.ConnectPeer(id, peer)
+		if err != nil {
+			var rpcErr RPCError
+			if !errors.As(err, &rpcErr) {
+				return torrentErrors, err
+			}
+			torrentErrors = append(torrentErrors, TorrentError{ID: id, Message: rpcErr.ExceptionMessage})
+		}
+	}
+
+	return torrentErrors, nil
+}
 
-	var trackers rencode.List
-	trackers.Add(tracker)
+// SessionState returns the current session state.
+func (c *Client) SessionState() ([]string, error) {
+	return c.rpcWithStringsResult("core.get_session_state")
+}
 
+// SetTorrentOptions updates options for the torrent with the given hash.
+func (c *Client) SetTorrentOptions(id string, options *Options) error {
 	var args rencode.List
-	args.Add(id, trackers)
+	args.Add(id, options.toDictionary(c.v2daemon))
 
-	resp, err := c.rpc("core.set_torrent_trackers", args, rencode.Dictionary{})
+	resp, err := c.rpc("core.set_torrent_options", args, rencode.Dictionary{})
 	if err != nil {
 		return err
 	}
@@ -432,6 +511,54 @@ func (c *ClientV2) RemoveAccount(username string) (bool, error) {
 	return success.(bool), nil
 }
 
+// GetAuthLevelsMappings returns the numeric value of each auth level known to the daemon.
+// The "DEFAULT" level reported by the daemon is an alias and is returned as AuthLevelDefault.
+func (c *ClientV2) GetAuthLevelsMappings() (map[AuthLevel]int64, error) {
+	resp, err := c.rpc("core.get_auth_levels_mappings", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, resp.RPCError
+	}
+
+	// a tuple of the mapping and its reverse is returned
+	var mappings rencode.List
+	err = resp.returnValue.Scan(&mappings)
+	if err != nil {
+		return nil, err
+	}
+	var mapping rencode.Dictionary
+	err = mappings.Scan(&mapping)
+	if err != nil {
+		return nil, err
+	}
+	values, err := mapping.Zip()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[AuthLevel]int64, len(values))
+	for name, v := range values {
+		var value int64
+		l := rencode.NewList(v)
+		err = l.Scan(&value)
+		if err != nil {
+			return nil, err
+		}
+		if name == "DEFAULT" {
+			// the daemon value of the level it aliases takes precedence
+			if _, ok := values[string(AuthLevelDefault)]; !ok {
+				result[AuthLevelDefault] = value
+			}
+			continue
+		}
+		result[AuthLevel(name)] = value
+	}
+
+	return result, nil
+}
+
 // ForceReannounce will reannounce torrent status to associated tracker(s).
 func (c *Client) ForceReannounce(ids []string) error {
 	var args rencode.List
@@ -448,6 +575,23 @@ func (c *Client) ForceReannounce(ids []string) error {
 	return nil
 }
 
+// ForceRecheck will verify the downloaded data of the torrents with the given IDs.
+// See ForceRecheckAndWait to follow the progress of the check.
+func (c *Client) ForceRecheck(ids []string) error {
+	var args rencode.List
+	args.Add(sliceToRencodeList(ids))
+
+	resp, err := c.rpc("core.force_recheck", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
 // GetEnabledPlugins returns a list of enabled plugins.
 func (c *Client) GetEnabledPlugins() ([]string, error) {
 	return c.rpcWithStringsResult("core.get_enabled_plugins")
@@ -494,6 +638,58 @@ func (c *Client) DisablePlugin(name string) error {
 	return nil
 }
 
+// UploadPlugin uploads a plugin .egg file to the daemon; RescanPlugins must be called
+// afterwards for it to become available.
+func (c *Client) UploadPlugin(fileName string, r io.Reader) error {
+	var buf bytes.Buffer
+	enc := base64.NewEncoder(base64.StdEncoding, &buf)
+	_, err := io.Copy(enc, r)
+	if err != nil {
+		return err
+	}
+	err = enc.Close()
+	if err != nil {
+		return err
+	}
+
+	var args rencode.List
+	args.Add(fileName, buf.String())
+
+	resp, err := c.rpc("core.upload_plugin", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// UploadPluginFile uploads the plugin .egg file at the specified local path to the daemon.
+func (c *Client) UploadPluginFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.UploadPlugin(filepath.Base(path), f)
+}
+
+// RescanPlugins makes the daemon rescan the plugin folders for new plugins.
+func (c *Client) RescanPlugins() error {
+	resp, err := c.rpc("core.rescan_plugins", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
 func sliceToRencodeList(s []string) rencode.List {
 	var list rencode.List
 	for _, v := range s {
@@ -15,7 +15,11 @@
 package delugeclient
 
 import (
+	"errors"
+	"net/netip"
 	"testing"
+
+	"github.com/gdm85/go-rencode"
 )
 
 func TestConnect(t *testing.T) {
@@ -214,3 +218,150 @@ func TestGetSessionStatus(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestAuthLevel(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClient(true, 0)
+	conn.addResponse(1, 10)
+
+	err := c.DaemonLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.AuthLevel() != AuthLevelAdmin {
+		t.Errorf("expected auth level %q but got %q", AuthLevelAdmin, c.AuthLevel())
+	}
+	if AuthLevelReadonly.Value() != 1 {
+		t.Errorf("expected value 1 but got %d", AuthLevelReadonly.Value())
+	}
+}
+
+func TestDaemonAuthorizedCall(t *testing.T) {
+	t.Parallel()
+
+	// also exported by v1 daemons
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, false)
+
+	authorized, err := c.DaemonAuthorizedCall("daemon.shutdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorized {
+		t.Error("expected call to not be authorized")
+	}
+	method, args, _ := conn.lastMethod()
+	if method != "daemon.authorized_call" || args.Length() != 1 {
+		t.Errorf("unexpected request %s%v", method, args.Values())
+	}
+}
+
+func TestGetAuthLevelsMappings(t *testing.T) {
+	t.Parallel()
+
+	var mapping, reverse rencode.Dictionary
+	mapping.Add("NONE", 0)
+	mapping.Add("READONLY", 1)
+	mapping.Add("DEFAULT", 5)
+	mapping.Add("NORMAL", 5)
+	mapping.Add("ADMIN", 10)
+	reverse.Add(0, "NONE")
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList(mapping, reverse))
+
+	m, err := c.GetAuthLevelsMappings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 4 || m[AuthLevelAdmin] != 10 || m[AuthLevelDefault] != 5 {
+		t.Errorf("unexpected mapping %v", m)
+	}
+	if _, ok := m["DEFAULT"]; ok {
+		t.Error("expected the DEFAULT alias to not be returned as an auth level")
+	}
+}
+
+func TestPauseSession(t *testing.T) {
+	t.Parallel()
+
+	for _, v2daemon := range []bool{false, true} {
+		c, conn := newMockConnClient(v2daemon, 0)
+		conn.addResponse(1, nil)
+
+		err := c.PauseSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "core.pause_session"
+		if !v2daemon {
+			expected = "core.pause_all_torrents"
+		}
+		if method, _, _ := conn.lastMethod(); method != expected {
+			t.Errorf("expected method %q but got %q", expected, method)
+		}
+	}
+}
+
+func TestIsSessionPaused(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, true)
+
+	paused, err := c.IsSessionPaused()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !paused {
+		t.Error("expected session to be paused")
+	}
+}
+
+func TestConnectPeers(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, nil)
+	conn.addError(2, "KeyError", "torrent not found")
+
+	peer := netip.MustParseAddrPort("[2001:db8::1]:6881")
+	torrentErrors, err := c.ConnectPeers([]string{"a", "b"}, peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(torrentErrors) != 1 || torrentErrors[0].ID != "b" {
+		t.Errorf("unexpected torrent errors %v", torrentErrors)
+	}
+
+	var (
+		id, addr string
+		port     int
+	)
+	method, args, _ := conn.requestAt(0)
+	err = args.Scan(&id, &addr, &port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "core.connect_peer" || id != "a" || addr != "2001:db8::1" || port != 6881 {
+		t.Errorf("unexpected request %s(%s, %s, %d)", method, id, addr, port)
+	}
+}
+
+func TestConnectPeerInvalid(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+
+	for _, peer := range []netip.AddrPort{{}, netip.AddrPortFrom(netip.MustParseAddr("192.0.2.1"), 0)} {
+		err := c.ConnectPeer("a", peer)
+		if !errors.Is(err, ErrInvalidPeer) {
+			t.Errorf("expected ErrInvalidPeer for %v, got %v", peer, err)
+		}
+	}
+	if len(conn.sentRequests()) != 0 {
+		t.Error("expected no requests to be sent")
+	}
+}
@@ -1,8 +1,15 @@
 package delugeclient
 
 import (
+	"bufio"
 	"bytes"
+	"compress/zlib"
+	"encoding/binary"
 	"encoding/hex"
+	"io"
+	"reflect"
+
+	"github.com/gdm85/go-rencode"
 )
 
 // buffer is just here to make bytes.Buffer an io.ReadWriteCloser.
@@ -46,3 +53,187 @@ func newMockClientV2(serial int64, payload string) V2 {
 
 	return &c
 }
+
+// mockConn serves pre-encoded server messages and records the requests written to it.
+type mockConn struct {
+	v2daemon  bool
+	responses bytes.Buffer
+	requests  bytes.Buffer
+	// oneByteReads makes each Read return at most one byte, to simulate short reads
+	oneByteReads bool
+}
+
+func (m *mockConn) Read(p []byte) (int, error) {
+	if m.oneByteReads && len(p) > 1 {
+		p = p[:1]
+	}
+	return m.responses.Read(p)
+}
+
+func (m *mockConn) Write(p []byte) (int, error) {
+	return m.requests.Write(p)
+}
+
+func (m *mockConn) Close() error {
+	return nil
+}
+
+// addMessage encodes a server message using the framing of the mocked daemon.
+func (m *mockConn) addMessage(message rencode.List) {
+	var body bytes.Buffer
+	zw := zlib.NewWriter(&body)
+	e := rencode.NewEncoder(zw)
+	err := e.Encode(message)
+	if err != nil {
+		panic(err)
+	}
+	err = zw.Close()
+	if err != nil {
+		panic(err)
+	}
+
+	if m.v2daemon {
+		var header [5]byte
+		header[0] = Deluge2ProtocolVersion
+		binary.BigEndian.PutUint32(header[1:], uint32(body.Len()))
+		m.responses.Write(header[:])
+	}
+	m.responses.Write(body.Bytes())
+}
+
+// addResponse adds a successful response for the request with the specified serial.
+func (m *mockConn) addResponse(serial int64, values ...interface{}) {
+	m.addMessage(rencode.NewList(append([]interface{}{int(rpcResponse), serial}, values...)...))
+}
+
+// addError adds an error response for the request with the specified serial.
+func (m *mockConn) addError(serial int64, exceptionType, message string) {
+	if m.v2daemon {
+		m.addMessage(rencode.NewList(int(rpcError), serial, exceptionType, rencode.NewList(message), rencode.Dictionary{}, "traceback"))
+		return
+	}
+	m.addMessage(rencode.NewList(int(rpcError), serial, rencode.NewList(exceptionType, message, "traceback")))
+}
+
+// sentRequests decodes all the requests written so far, each as a list
+// of serial, method name, arguments and keyword arguments.
+func (m *mockConn) sentRequests() []rencode.List {
+	var result []rencode.List
+	r := bufio.NewReader(bytes.NewReader(m.requests.Bytes()))
+	for {
+		if m.v2daemon {
+			var header [5]byte
+			_, err := io.ReadFull(r, header[:])
+			if err == io.EOF {
+				return result
+			}
+			if err != nil {
+				panic(err)
+			}
+		} else if _, err := r.Peek(1); err == io.EOF {
+			return result
+		}
+
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			panic(err)
+		}
+		var outer, inner rencode.List
+		err = rencode.NewDecoder(zr).Scan(&outer)
+		if err != nil {
+			panic(err)
+		}
+		err = outer.Scan(&inner)
+		if err != nil {
+			panic(err)
+		}
+		_, err = io.Copy(io.Discard, zr)
+		if err != nil {
+			panic(err)
+		}
+		result = append(result, inner)
+	}
+}
+
+// lastMethod returns the method name and arguments of the last request sent.
+func (m *mockConn) lastMethod() (string, rencode.List, rencode.Dictionary) {
+	requests := m.sentRequests()
+	if len(requests) == 0 {
+		panic("no requests sent")
+	}
+	return m.requestAt(len(requests) - 1)
+}
+
+// requestAt returns the method name and arguments of the i-th request sent.
+func (m *mockConn) requestAt(i int) (string, rencode.List, rencode.Dictionary) {
+	var (
+		serial int64
+		method string
+		args   rencode.List
+		kwargs rencode.Dictionary
+	)
+	err := m.sentRequests()[i].Scan(&serial, &method, &args, &kwargs)
+	if err != nil {
+		panic(err)
+	}
+	return method, args, kwargs
+}
+
+// newMockConnClient returns a client connected to a mocked connection; the serial
+// of the next request will be serial+1.
+func newMockConnClient(v2daemon bool, serial int64) (*Client, *mockConn) {
+	var c *Client
+	if v2daemon {
+		c = &NewV2(Settings{}).Client
+	} else {
+		c = NewV1(Settings{})
+	}
+	conn := &mockConn{v2daemon: v2daemon}
+	c.serial = serial
+	c.safeConn = conn
+
+	return c, conn
+}
+
+// newMockConnClientV2 is the v2 equivalent of newMockConnClient.
+func newMockConnClientV2(serial int64) (*ClientV2, *mockConn) {
+	c := NewV2(Settings{})
+	conn := &mockConn{v2daemon: true}
+	c.serial = serial
+	c.safeConn = conn
+
+	return c, conn
+}
+
+// testStatusDictionary returns a dictionary of alternating keys and values, like a torrent status
+// or the statuses of torrents indexed by hash; structs and slices of structs, like File or Tracker,
+// are converted to dictionaries with the snake case names of their fields as keys.
+func testStatusDictionary(values ...interface{}) rencode.Dictionary {
+	var d rencode.Dictionary
+	for i := 0; i < len(values); i += 2 {
+		d.Add(values[i], testStatusValue(reflect.ValueOf(values[i+1])))
+	}
+	return d
+}
+
+func testStatusValue(v reflect.Value) interface{} {
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Type() == reflect.TypeOf(rencode.Dictionary{}) || v.Type() == reflect.TypeOf(rencode.List{}):
+		return v.Interface()
+	case v.Kind() == reflect.Struct:
+		var d rencode.Dictionary
+		for i := 0; i < v.NumField(); i++ {
+			d.Add(rencode.ToSnakeCase(v.Type().Field(i).Name), testStatusValue(v.Field(i)))
+		}
+		return d
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		var list rencode.List
+		for i := 0; i < v.Len(); i++ {
+			list.Add(testStatusValue(v.Index(i)))
+		}
+		return list
+	}
+	return v.Interface()
+}
@@ -0,0 +1,170 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"github.com/gdm85/go-rencode"
+)
+
+// ProxyType is the type of proxy used by the daemon.
+type ProxyType int64
+
+// The proxy types, as defined in
+// https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/preferencesmanager.py#L361-L370
+const (
+	ProxyNone       ProxyType = 0
+	ProxySocks4     ProxyType = 1
+	ProxySocks5     ProxyType = 2
+	ProxySocks5Auth ProxyType = 3
+	ProxyHTTP       ProxyType = 4
+	ProxyHTTPAuth   ProxyType = 5
+	ProxyI2P        ProxyType = 6
+)
+
+// Proxy contains the proxy settings of the daemon.
+type Proxy struct {
+	Type                    ProxyType
+	Hostname                string
+	Port                    int64
+	Username                string
+	Password                string
+	ProxyHostnames          bool
+	ProxyPeerConnections    bool
+	ProxyTrackerConnections bool
+}
+
+// NetworkInfo contains the network settings and state of the daemon.
+type NetworkInfo struct {
+	// ExternalIP is the external IP address as seen by libtorrent, empty when not yet known;
+	// it is always empty on v1 daemons
+	ExternalIP        string
+	ListenPort        uint16
+	ListenInterface   string
+	OutgoingInterface string // v2-only
+	RandomPort        bool
+	UPnP              bool
+	NATPMP            bool
+	// Proxy is nil on v1 daemons
+	Proxy *Proxy
+}
+
+// GetExternalIP returns the external IP address as seen by libtorrent, or an empty
+// string if not yet known.
+func (c *ClientV2) GetExternalIP() (string, error) {
+	return c.getExternalIP()
+}
+
+func (c *Client) getExternalIP() (string, error) {
+	resp, err := c.rpc("core.get_external_ip", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", resp.RPCError
+	}
+
+	vals := resp.returnValue.Values()
+	if len(vals) == 0 {
+		return "", ErrInvalidReturnValue
+	}
+	if vals[0] == nil {
+		return "", nil
+	}
+	ip, ok := vals[0].([]byte)
+	if !ok {
+		return "", ErrInvalidReturnValue
+	}
+
+	return string(ip), nil
+}
+
+// GetProxy returns the proxy settings of the daemon.
+func (c *ClientV2) GetProxy() (*Proxy, error) {
+	return c.getProxy()
+}
+
+func (c *Client) getProxy() (*Proxy, error) {
+	rd, err := c.rpcWithDictionaryResult("core.get_proxy", rencode.List{}, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := rd.Zip()
+	if err != nil {
+		return nil, err
+	}
+
+	var p Proxy
+	err = decodeStruct(d, &p, c.excludeTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// networkConfig contains the configuration values reported by NetworkInfo.
+type networkConfig struct {
+	ListenInterface   string
+	OutgoingInterface string `rencode:"v2only"`
+	RandomPort        bool
+	Upnp              bool
+	Natpmp            bool
+}
+
+// NetworkInfo returns the network settings and state of the daemon.
+func (c *Client) NetworkInfo() (*NetworkInfo, error) {
+	var info NetworkInfo
+
+	port, err := c.GetListenPort()
+	if err != nil {
+		return nil, err
+	}
+	info.ListenPort = port
+
+	keys := []string{"listen_interface", "random_port", "upnp", "natpmp"}
+	if c.v2daemon {
+		keys = append(keys, "outgoing_interface")
+	}
+	d, err := c.GetConfigValues(keys...)
+	if err != nil {
+		return nil, err
+	}
+	var cfg networkConfig
+	err = decodeStruct(d, &cfg, c.excludeTag)
+	if err != nil {
+		return nil, err
+	}
+	info.ListenInterface = cfg.ListenInterface
+	info.OutgoingInterface = cfg.OutgoingInterface
+	info.RandomPort = cfg.RandomPort
+	info.UPnP = cfg.Upnp
+	info.NATPMP = cfg.Natpmp
+
+	if !c.v2daemon {
+		return &info, nil
+	}
+
+	info.ExternalIP, err = c.getExternalIP()
+	if err != nil {
+		return nil, err
+	}
+	info.Proxy, err = c.getProxy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
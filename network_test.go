@@ -0,0 +1,84 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestNetworkInfo(t *testing.T) {
+	t.Parallel()
+
+	var config rencode.Dictionary
+	config.Add("listen_interface", "10.8.0.2")
+	config.Add("outgoing_interface", "tun0")
+	config.Add("random_port", false)
+	config.Add("upnp", true)
+	config.Add("natpmp", false)
+
+	var proxy rencode.Dictionary
+	proxy.Add("type", 2)
+	proxy.Add("hostname", "proxy.local")
+	proxy.Add("port", 1080)
+	proxy.Add("username", "")
+	proxy.Add("password", "")
+	proxy.Add("proxy_hostnames", true)
+	proxy.Add("proxy_peer_connections", true)
+	proxy.Add("proxy_tracker_connections", true)
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, 6881)
+	conn.addResponse(2, config)
+	conn.addResponse(3, "198.51.100.7")
+	conn.addResponse(4, proxy)
+
+	info, err := c.NetworkInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ListenPort != 6881 || info.ListenInterface != "10.8.0.2" || info.OutgoingInterface != "tun0" || !info.UPnP {
+		t.Errorf("unexpected network info %+v", info)
+	}
+	if info.ExternalIP != "198.51.100.7" {
+		t.Errorf("expected external IP %q, got %q", "198.51.100.7", info.ExternalIP)
+	}
+	if info.Proxy == nil || info.Proxy.Type != ProxySocks5 || info.Proxy.Port != 1080 {
+		t.Errorf("unexpected proxy settings %+v", info.Proxy)
+	}
+}
+
+func TestNetworkInfoV1(t *testing.T) {
+	t.Parallel()
+
+	var config rencode.Dictionary
+	config.Add("listen_interface", "")
+	config.Add("random_port", true)
+	config.Add("upnp", true)
+	config.Add("natpmp", true)
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, 6881)
+	conn.addResponse(2, config)
+
+	info, err := c.NetworkInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Proxy != nil || info.ExternalIP != "" || !info.RandomPort {
+		t.Errorf("unexpected network info %+v", info)
+	}
+}
@@ -38,6 +38,8 @@ type Options struct {
 	MoveCompleted             *bool
 	MoveCompletedPath         *string
 	AddPaused                 *bool
+	// FilePriorities contains the priority of each file, in the order of the file indexes
+	FilePriorities []FilePriority
 
 	// V2 defines v2-only options
 	V2 V2Options
@@ -73,6 +75,15 @@ func (o *Options) toDictionary(v2daemon bool) rencode.Dictionary {
 			name = "compact_allocation"
 		}
 
+		if name == "file_priorities" {
+			var list rencode.List
+			for _, p := range o.FilePriorities {
+				list.Add(filePriorityToWire(p, v2daemon))
+			}
+			dict.Add(name, list)
+			continue
+		}
+
 		dict.Add(name, reflect.Indirect(f).Interface())
 	}
 
@@ -15,7 +15,10 @@
 package delugeclient
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/gdm85/go-rencode"
 )
 
 var testOpts Options
@@ -127,3 +130,37 @@ func TestOptionsEncodeV2(t *testing.T) {
 
 	}
 }
+
+func TestOptionsEncodeFilePriorities(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{
+		FilePriorities: []FilePriority{FilePrioritySkip, FilePriorityNormal, FilePriorityHigh},
+	}
+
+	d := opts.toDictionary(true)
+
+	m, err := d.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	priorities, ok := m["file_priorities"].(rencode.List)
+	if !ok {
+		t.Fatalf("expected key %q to be a list", "file_priorities")
+	}
+	if !reflect.DeepEqual(priorities.Values(), []interface{}{int64(0), int64(4), int64(7)}) {
+		t.Errorf("unexpected file priorities %v", priorities.Values())
+	}
+
+	// v1 uses a different scale
+	d = opts.toDictionary(false)
+	m, err = d.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priorities = m["file_priorities"].(rencode.List)
+	if !reflect.DeepEqual(priorities.Values(), []interface{}{int64(0), int64(1), int64(2)}) {
+		t.Errorf("unexpected v1 file priorities %v", priorities.Values())
+	}
+}
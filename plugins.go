@@ -15,9 +15,80 @@
 package delugeclient
 
 import (
+	"errors"
+	"io"
+	"strings"
+
 	"github.com/gdm85/go-rencode"
 )
 
+// ErrPluginNotFound is returned by InstallPlugin when the uploaded plugin is not available after a rescan.
+var ErrPluginNotFound = errors.New("plugin not found after upload")
+
+// InstallPlugin uploads a plugin .egg file, rescans the plugins and enables the plugin,
+// returning its name.
+// The plugin is identified as the one which became available after the upload, or by
+// the name in the .egg file name (e.g. "Label-0.3-py3.7.egg") when it was already available.
+func (c *Client) InstallPlugin(fileName string, r io.Reader) (string, error) {
+	before, err := c.GetAvailablePlugins()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.UploadPlugin(fileName, r)
+	if err != nil {
+		return "", err
+	}
+	err = c.RescanPlugins()
+	if err != nil {
+		return "", err
+	}
+
+	after, err := c.GetAvailablePlugins()
+	if err != nil {
+		return "", err
+	}
+
+	name := newPluginName(before, after, fileName)
+	if name == "" {
+		return "", ErrPluginNotFound
+	}
+
+	err = c.EnablePlugin(name)
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// newPluginName returns the name of the plugin which was made available by uploading fileName.
+func newPluginName(before, after []string, fileName string) string {
+	known := make(map[string]struct{}, len(before))
+	for _, p := range before {
+		known[p] = struct{}{}
+	}
+	var added []string
+	for _, p := range after {
+		if _, ok := known[p]; !ok {
+			added = append(added, p)
+		}
+	}
+	if len(added) == 1 {
+		return added[0]
+	}
+
+	// egg files are named after the plugin, followed by version and Python version
+	eggName := strings.SplitN(fileName, "-", 2)[0]
+	for _, p := range after {
+		if strings.EqualFold(strings.ReplaceAll(p, " ", ""), eggName) {
+			return p
+		}
+	}
+
+	return ""
+}
+
 // LabelPlugin exposes label plugin methods.
 type LabelPlugin struct {
 	*Client
@@ -118,15 +189,14 @@ func (p LabelPlugin) GetTorrentLabel(hash string) (string, error) {
 
 // GetTorrentsLabels filters torrents by state and/or IDs and returns their label.
 func (p LabelPlugin) GetTorrentsLabels(state TorrentState, ids []string) (map[string]string, error) {
+	return p.FilterTorrentsLabels(&Filter{State: state, IDs: ids})
+}
+
+// FilterTorrentsLabels returns the label of the torrents matching the filter;
+// unlabeled torrents have an empty label.
+func (p LabelPlugin) FilterTorrentsLabels(filter *Filter) (map[string]string, error) {
 	var args rencode.List
-	var filterDict rencode.Dictionary
-	if len(ids) != 0 {
-		filterDict.Add("id", sliceToRencodeList(ids))
-	}
-	if state != StateUnspecified {
-		filterDict.Add("state", string(state))
-	}
-	args.Add(filterDict)
+	args.Add(filter.toDictionary())
 	args.Add(rencode.NewList("label"))
 
 	rd, err := p.rpcWithDictionaryResult("core.get_torrents_status", args, rencode.Dictionary{})
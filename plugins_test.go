@@ -1,6 +1,12 @@
 package delugeclient
 
-import "testing"
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
 
 func TestLabelPlugin_GetLabels(t *testing.T) {
 	t.Parallel()
@@ -34,3 +40,42 @@ func TestLabelPlugin_GetLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestInstallPlugin(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClient(true, 0)
+	conn.addResponse(1, rencode.NewList("Label"))
+	conn.addResponse(2, nil)
+	conn.addResponse(3, nil)
+	conn.addResponse(4, rencode.NewList("Label", "AutoAdd"))
+	conn.addResponse(5, true)
+
+	name, err := c.InstallPlugin("AutoAdd-1.8-py3.7.egg", strings.NewReader("egg content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "AutoAdd" {
+		t.Errorf("expected plugin %q, got %q", "AutoAdd", name)
+	}
+
+	var fileName, fileDump string
+	method, args, _ := conn.requestAt(1)
+	err = args.Scan(&fileName, &fileDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "core.upload_plugin" || fileDump != base64.StdEncoding.EncodeToString([]byte("egg content")) {
+		t.Errorf("unexpected request %s(%s, %s)", method, fileName, fileDump)
+	}
+}
+
+func TestNewPluginName(t *testing.T) {
+	t.Parallel()
+
+	// an upgraded plugin is already available before the upload
+	name := newPluginName([]string{"Label", "Auto Add"}, []string{"Label", "Auto Add"}, "AutoAdd-1.9-py3.7.egg")
+	if name != "Auto Add" {
+		t.Errorf("expected plugin %q, got %q", "Auto Add", name)
+	}
+}
@@ -0,0 +1,131 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"github.com/gdm85/go-rencode"
+)
+
+// QueueTop moves the torrents with the given IDs to the top of the queue.
+func (c *Client) QueueTop(ids ...string) error {
+	return c.queueMove("core.queue_top", ids)
+}
+
+// QueueUp moves the torrents with the given IDs one position up in the queue.
+func (c *Client) QueueUp(ids ...string) error {
+	return c.queueMove("core.queue_up", ids)
+}
+
+// QueueDown moves the torrents with the given IDs one position down in the queue.
+func (c *Client) QueueDown(ids ...string) error {
+	return c.queueMove("core.queue_down", ids)
+}
+
+// QueueBottom moves the torrents with the given IDs to the bottom of the queue.
+func (c *Client) QueueBottom(ids ...string) error {
+	return c.queueMove("core.queue_bottom", ids)
+}
+
+func (c *Client) queueMove(method string, ids []string) error {
+	var args rencode.List
+	args.Add(sliceToRencodeList(ids))
+
+	resp, err := c.rpc(method, args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// queuePositions returns the queue position of the torrents with the given IDs;
+// torrents which are not queued (e.g. seeding) have a negative position.
+func (c *Client) queuePositions(ids []string) (map[string]int64, error) {
+	var filterDict rencode.Dictionary
+	filterDict.Add("id", sliceToRencodeList(ids))
+
+	d, err := c.torrentsStatusDictionaries(filterDict, rencode.NewList("queue"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]int64{}
+	for k, v := range d {
+		var s struct {
+			Queue int64
+		}
+		err = v.ToStruct(&s, "")
+		if err != nil {
+			return nil, err
+		}
+		result[k] = s.Queue
+	}
+
+	return result, nil
+}
+
+// ReorderQueue reorders the queue so that the torrents with the given IDs
+// follow the specified order relative to each other; torrents which are not
+// part of the queue (e.g. seeding ones) are ignored.
+// The given order is split into runs of torrents which are already correctly
+// ordered: the first run stays in place and each following run is moved to the
+// bottom of the queue, with an RPC call per run.
+// As a side effect, the moved torrents end up below all the queued torrents which
+// are not in the given IDs, even those that were queued after them; the relative order
+// of the other torrents is preserved. QueueUp and QueueDown can be used instead to move
+// torrents one position at a time.
+func (c *Client) ReorderQueue(ids []string) error {
+	positions, err := c.queuePositions(ids)
+	if err != nil {
+		return err
+	}
+
+	runs := queueRuns(ids, positions)
+
+	// the first run stays in place, above all the others
+	for i := 1; i < len(runs); i++ {
+		err = c.QueueBottom(runs[i]...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueRuns splits the queued torrents in the desired order into runs of
+// torrents whose current queue positions are increasing.
+func queueRuns(ids []string, positions map[string]int64) [][]string {
+	var (
+		runs [][]string
+		last int64 = -1
+	)
+	for _, id := range ids {
+		pos, ok := positions[id]
+		if !ok || pos < 0 {
+			continue
+		}
+		if len(runs) == 0 || pos < last {
+			runs = append(runs, nil)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], id)
+		last = pos
+	}
+
+	return runs
+}
@@ -0,0 +1,76 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestQueueRuns(t *testing.T) {
+	t.Parallel()
+
+	positions := map[string]int64{"a": 0, "b": 1, "c": 2, "d": 3, "seeding": -1}
+
+	runs := queueRuns([]string{"a", "b", "c", "d"}, positions)
+	if len(runs) != 1 {
+		t.Errorf("expected a single run for an ordered queue, got %v", runs)
+	}
+
+	runs = queueRuns([]string{"c", "d", "seeding", "a", "b"}, positions)
+	expected := [][]string{{"c", "d"}, {"a", "b"}}
+	if !reflect.DeepEqual(runs, expected) {
+		t.Errorf("expected runs %v, got %v", expected, runs)
+	}
+}
+
+func TestReorderQueue(t *testing.T) {
+	t.Parallel()
+
+	var statuses rencode.Dictionary
+	for hash, queue := range map[string]int{"a": 0, "b": 1, "c": 2} {
+		var status rencode.Dictionary
+		status.Add("queue", queue)
+		statuses.Add(hash, status)
+	}
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, statuses)
+	conn.addResponse(2, nil)
+	conn.addResponse(3, nil)
+
+	err := c.ReorderQueue([]string{"c", "b", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := conn.sentRequests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	for i, expected := range []string{"b", "a"} {
+		method, args, _ := conn.requestAt(i + 1)
+		var ids rencode.List
+		err = args.Scan(&ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if method != "core.queue_bottom" || ids.Length() != 1 || string(ids.Values()[0].([]byte)) != expected {
+			t.Errorf("unexpected request %s%v", method, args.Values())
+		}
+	}
+}
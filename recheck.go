@@ -0,0 +1,172 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// ErrRecheckTimeout is returned by ForceRecheckAndWait when the checks did not finish in time.
+var ErrRecheckTimeout = errors.New("timeout waiting for torrents to be checked")
+
+// RecheckProgressFunc is called by ForceRecheckAndWait with the state and progress
+// of each torrent being checked.
+type RecheckProgressFunc func(id string, state TorrentState, progress float32)
+
+// RecheckResult is the outcome of ForceRecheckAndWait.
+type RecheckResult struct {
+	// Complete contains the torrents whose data is fully available
+	Complete []string
+	// Incomplete contains the torrents which are missing some data after the check
+	Incomplete []string
+	// Errors contains the torrents which ended up in the error state or were removed during the check
+	Errors []TorrentError
+	// Pending contains the torrents whose check did not finish before the timeout
+	Pending []string
+}
+
+// recheckStatus contains the torrent attributes used to follow a data check.
+type recheckStatus struct {
+	State    string
+	Progress float32
+	Message  string
+}
+
+var recheckStatusKeys = rencode.NewList("state", "progress", "message")
+
+// recheckStartPolls is the number of polls after which a torrent whose status did not change since
+// the recheck was requested is considered checked, as its check was too fast to be noticed.
+const recheckStartPolls = 3
+
+// recheckStatuses returns the status of the torrents matching the filter.
+func (c *Client) recheckStatuses(filterDict rencode.Dictionary) (map[string]recheckStatus, error) {
+	d, err := c.torrentsStatusDictionaries(filterDict, recheckStatusKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]recheckStatus, len(d))
+	for id, v := range d {
+		var st recheckStatus
+		err = v.ToStruct(&st, "")
+		if err != nil {
+			return nil, err
+		}
+		result[id] = st
+	}
+
+	return result, nil
+}
+
+// ForceRecheckAndWait will verify the downloaded data of the torrents with the given IDs
+// and poll their status every pollInterval until all of them have been checked.
+// The daemon starts the checks asynchronously, thus a torrent is considered checked once it has been
+// seen in the checking or allocating state, or once its state or progress differ from the ones before
+// the recheck; a torrent whose status never changes is considered checked after a few polls.
+// If the checks did not finish within the timeout, the partial result is returned together with
+// ErrRecheckTimeout; a zero timeout waits indefinitely.
+// The optional progress function is called for each torrent after every poll.
+func (c *Client) ForceRecheckAndWait(ids []string, pollInterval, timeout time.Duration, progress RecheckProgressFunc) (*RecheckResult, error) {
+	var filterDict rencode.Dictionary
+	filterDict.Add("id", sliceToRencodeList(ids))
+
+	before, err := c.recheckStatuses(filterDict)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.ForceRecheck(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	// the number of polls which found each torrent unchanged; -1 once its check started
+	pending := make(map[string]int, len(ids))
+	for _, id := range ids {
+		pending[id] = 0
+	}
+
+	var result RecheckResult
+	for {
+		statuses, err := c.recheckStatuses(filterDict)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			unchanged, ok := pending[id]
+			if !ok {
+				continue
+			}
+
+			st, ok := statuses[id]
+			if !ok {
+				delete(pending, id)
+				result.Errors = append(result.Errors, TorrentError{ID: id, Message: "torrent not found"})
+				continue
+			}
+			state := TorrentState(st.State)
+
+			if progress != nil {
+				progress(id, state, st.Progress)
+			}
+
+			switch state {
+			case StateChecking, StateAllocating:
+				pending[id] = -1
+				continue
+			case StateError:
+				result.Errors = append(result.Errors, TorrentError{ID: id, Message: st.Message})
+			default:
+				prev, known := before[id]
+				if unchanged >= 0 && known && st.State == prev.State && st.Progress == prev.Progress {
+					unchanged++
+					if unchanged < recheckStartPolls {
+						// the check might not have started yet
+						pending[id] = unchanged
+						continue
+					}
+				}
+				if st.Progress < 100 {
+					result.Incomplete = append(result.Incomplete, id)
+				} else {
+					result.Complete = append(result.Complete, id)
+				}
+			}
+			delete(pending, id)
+		}
+
+		if len(pending) == 0 {
+			return &result, nil
+		}
+		if !deadline.IsZero() && time.Now().Add(pollInterval).After(deadline) {
+			for _, id := range ids {
+				if _, ok := pending[id]; ok {
+					result.Pending = append(result.Pending, id)
+				}
+			}
+			return &result, ErrRecheckTimeout
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,96 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestForceRecheckAndWait(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(
+		"a", recheckStatus{"Seeding", 100, "OK"},
+		"b", recheckStatus{"Paused", 40, "OK"},
+		"c", recheckStatus{"Seeding", 100, "OK"},
+		"d", recheckStatus{"Paused", 0, "OK"},
+	))
+	conn.addResponse(2, nil)
+	// the check of a is never noticed, the one of d finished before the first poll
+	conn.addResponse(3, testStatusDictionary(
+		"a", recheckStatus{"Seeding", 100, "OK"},
+		"b", recheckStatus{"Checking", 10, "OK"},
+		"c", recheckStatus{"Error", 0, "No such file"},
+		"d", recheckStatus{"Paused", 30, "OK"},
+	))
+	conn.addResponse(4, testStatusDictionary(
+		"a", recheckStatus{"Seeding", 100, "OK"},
+		"b", recheckStatus{"Checking", 75, "OK"},
+	))
+	conn.addResponse(5, testStatusDictionary(
+		"a", recheckStatus{"Seeding", 100, "OK"},
+		"b", recheckStatus{"Paused", 75, "OK"},
+	))
+
+	var calls int
+	result, err := c.ForceRecheckAndWait([]string{"a", "b", "c", "d"}, 0, 0, func(id string, state TorrentState, progress float32) {
+		calls++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RecheckResult{
+		Complete:   []string{"a"},
+		Incomplete: []string{"d", "b"},
+		Errors:     []TorrentError{{ID: "c", Message: "No such file"}},
+	}
+	if !reflect.DeepEqual(*result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *result)
+	}
+	if calls != 8 {
+		t.Errorf("expected 8 progress calls, got %d", calls)
+	}
+}
+
+func TestForceRecheckAndWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(
+		"a", recheckStatus{"Seeding", 100, "OK"},
+		"b", recheckStatus{"Seeding", 100, "OK"},
+	))
+	conn.addResponse(2, nil)
+	conn.addResponse(3, testStatusDictionary(
+		"a", recheckStatus{"Checking", 50, "OK"},
+		"b", recheckStatus{"Error", 0, "No such file"},
+	))
+
+	result, err := c.ForceRecheckAndWait([]string{"a", "b"}, time.Second, time.Millisecond, nil)
+	if err != ErrRecheckTimeout {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	expected := RecheckResult{
+		Errors:  []TorrentError{{ID: "b", Message: "No such file"}},
+		Pending: []string{"a"},
+	}
+	if !reflect.DeepEqual(*result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *result)
+	}
+}
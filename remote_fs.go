@@ -0,0 +1,196 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// GetPathSize returns the size in bytes of the file or directory at the specified
+// path on the daemon host, or -1 if the path does not exist.
+// The daemon walks the whole directory tree before replying to any other call, which can take
+// a long time for large directories.
+func (c *Client) GetPathSize(path string) (int64, error) {
+	var args rencode.List
+	args.Add(path)
+
+	resp, err := c.rpc("core.get_path_size", args, rencode.Dictionary{})
+	if err != nil {
+		return 0, err
+	}
+	if resp.IsError() {
+		return 0, resp.RPCError
+	}
+
+	var size int64
+	err = resp.returnValue.Scan(&size)
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// GetCompletionPaths returns the paths on the daemon host which complete the specified text;
+// directories are returned with a trailing path separator.
+func (c *ClientV2) GetCompletionPaths(text string, showHiddenFiles bool) ([]string, error) {
+	var completionArgs rencode.Dictionary
+	completionArgs.Add("completion_text", text)
+	completionArgs.Add("show_hidden_files", showHiddenFiles)
+
+	var args rencode.List
+	args.Add(completionArgs)
+
+	rd, err := c.rpcWithDictionaryResult("core.get_completion_paths", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	// the arguments are returned with the additional paths key
+	d, err := rd.Zip()
+	if err != nil {
+		return nil, err
+	}
+	paths, ok := d["paths"].(rencode.List)
+	if !ok {
+		return nil, ErrInvalidReturnValue
+	}
+
+	return rencodeListToSlice(paths)
+}
+
+// Glob returns the paths on the daemon host matching the specified shell pattern.
+func (c *ClientV2) Glob(pattern string) ([]string, error) {
+	var args rencode.List
+	args.Add(pattern)
+
+	resp, err := c.rpc("core.glob", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, resp.RPCError
+	}
+
+	var paths rencode.List
+	err = resp.returnValue.Scan(&paths)
+	if err != nil {
+		return nil, err
+	}
+
+	return rencodeListToSlice(paths)
+}
+
+// RemoteDirectory describes a directory on the daemon host, as returned by BrowseDirectory.
+type RemoteDirectory struct {
+	Path string
+	// Exists is false when the directory does not exist, in which case all other fields are empty
+	Exists bool
+	// FreeSpace is the free space available in the filesystem of the directory
+	FreeSpace int64
+	// Directories are the full paths of the subdirectories, each with a trailing path separator
+	Directories []string
+	// Files are the full paths of the files
+	Files []string
+}
+
+// BrowseDirectory returns the content of a directory on the daemon host together with the
+// available free space, e.g. to choose a download location or a storage destination.
+// The size of the directory content is not computed, see GetPathSize.
+func (c *ClientV2) BrowseDirectory(path string, showHiddenFiles bool) (*RemoteDirectory, error) {
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	dir := RemoteDirectory{
+		Path: path,
+	}
+
+	// with the trailing separator only a directory matches
+	pattern := globEscape(path)
+	matches, err := c.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return &dir, nil
+	}
+	dir.Exists = true
+
+	dir.FreeSpace, err = c.GetFreeSpace(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// only directories are completed
+	dir.Directories, err = c.GetCompletionPaths(path, showHiddenFiles)
+	if err != nil {
+		return nil, err
+	}
+	isDir := make(map[string]bool, len(dir.Directories))
+	for _, p := range dir.Directories {
+		isDir[p] = true
+	}
+
+	patterns := []string{pattern + "*"}
+	if showHiddenFiles {
+		patterns = append(patterns, pattern+".*")
+	}
+	for _, pattern := range patterns {
+		matches, err := c.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range matches {
+			if !isDir[p+"/"] {
+				dir.Files = append(dir.Files, p)
+			}
+		}
+	}
+	sort.Strings(dir.Files)
+
+	return &dir, nil
+}
+
+// globEscape escapes the shell pattern special characters of the path, like Python's glob.escape.
+func globEscape(path string) string {
+	var sb strings.Builder
+	for _, r := range path {
+		switch r {
+		case '*', '?', '[':
+			sb.WriteByte('[')
+			sb.WriteRune(r)
+			sb.WriteByte(']')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func rencodeListToSlice(list rencode.List) ([]string, error) {
+	result := make([]string, list.Length())
+	for i, v := range list.Values() {
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, ErrInvalidReturnValue
+		}
+		result[i] = string(b)
+	}
+
+	return result, nil
+}
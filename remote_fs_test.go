@@ -0,0 +1,97 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestBrowseDirectory(t *testing.T) {
+	t.Parallel()
+
+	var completion rencode.Dictionary
+	completion.Add("completion_text", "/downloads/")
+	completion.Add("show_hidden_files", false)
+	completion.Add("paths", rencode.NewList("/downloads/movies/", "/downloads/tv/"))
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("/downloads/"))
+	conn.addResponse(2, int64(1<<40))
+	conn.addResponse(3, completion)
+	conn.addResponse(4, rencode.NewList("/downloads/movies", "/downloads/readme.txt", "/downloads/tv"))
+
+	dir, err := c.BrowseDirectory("/downloads", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RemoteDirectory{
+		Path:        "/downloads/",
+		Exists:      true,
+		FreeSpace:   1 << 40,
+		Directories: []string{"/downloads/movies/", "/downloads/tv/"},
+		Files:       []string{"/downloads/readme.txt"},
+	}
+	if !reflect.DeepEqual(*dir, expected) {
+		t.Errorf("expected %+v, got %+v", expected, *dir)
+	}
+
+	_, args, _ := conn.lastMethod()
+	if !reflect.DeepEqual(args.Values(), []interface{}{[]byte("/downloads/*")}) {
+		t.Errorf("unexpected glob arguments %v", args.Values())
+	}
+}
+
+func TestBrowseMissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.List{})
+
+	dir, err := c.BrowseDirectory("/missing/", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.Exists {
+		t.Error("expected directory to not exist")
+	}
+}
+
+func TestGlobEscape(t *testing.T) {
+	t.Parallel()
+
+	escaped := globEscape("/downloads/[2023] a*b?/")
+	if escaped != "/downloads/[[]2023] a[*]b[?]/" {
+		t.Errorf("unexpected escaped path %q", escaped)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, rencode.NewList("/downloads/a.torrent"))
+
+	paths, err := c.Glob("/downloads/*.torrent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "/downloads/a.torrent" {
+		t.Errorf("unexpected paths %v", paths)
+	}
+}
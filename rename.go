@@ -0,0 +1,326 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// RenameFiles renames files of the torrent with the given hash; renames maps
+// each file index, as in TorrentStatus.Files, to its new path.
+func (c *Client) RenameFiles(id string, renames map[int64]string) error {
+	indexes := make([]int64, 0, len(renames))
+	for index := range renames {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	var filenames rencode.List
+	for _, index := range indexes {
+		filenames.Add(rencode.NewList(index, renames[index]))
+	}
+
+	var args rencode.List
+	args.Add(id, filenames)
+
+	resp, err := c.rpc("core.rename_files", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// RenameFolder renames a folder of the torrent with the given hash; both folder
+// and newFolder are full paths inside the torrent.
+func (c *Client) RenameFolder(id, folder, newFolder string) error {
+	var args rencode.List
+	args.Add(id, folder, newFolder)
+
+	resp, err := c.rpc("core.rename_folder", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// RenameTarget selects which entries of a torrent a RenameRule applies to.
+type RenameTarget int
+
+const (
+	RenameTargetAll RenameTarget = iota
+	RenameTargetFiles
+	RenameTargetFolders
+)
+
+// RenameRule renames the files and folders of a torrent whose name matches Pattern.
+// Only the last element of each path is matched and renamed.
+// When Template is set it is executed with a RenameTemplateData to obtain the new
+// name, otherwise Replacement is expanded as with regexp.Regexp.ReplaceAllString.
+// A nil Pattern matches every name and requires a Template.
+type RenameRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+	Template    *template.Template
+	Target      RenameTarget
+}
+
+// RenameTemplateData is the data available to the template of a RenameRule.
+type RenameTemplateData struct {
+	// Name is the current name of the file or folder
+	Name string
+	// Stem is the name without extension
+	Stem string
+	// Ext is the extension of the name, including the leading dot
+	Ext string
+	// Submatches are the submatches of the rule pattern, the first one being the whole match
+	Submatches []string
+	// Index is the file index, or -1 for folders
+	Index int64
+}
+
+// RenameOperation is a single rename performed (or previewed) by BulkRename.
+type RenameOperation struct {
+	// Index is the file index, or -1 for folders
+	Index   int64
+	OldPath string
+	NewPath string
+}
+
+// ErrInvalidRename is returned when the rules of BulkRename produce an invalid or conflicting name.
+var ErrInvalidRename = errors.New("invalid rename")
+
+func (r RenameRule) apply(name string, index int64) (string, error) {
+	folder := index < 0
+	if (r.Target == RenameTargetFiles && folder) || (r.Target == RenameTargetFolders && !folder) {
+		return name, nil
+	}
+	submatches := []string{name}
+	if r.Pattern != nil {
+		submatches = r.Pattern.FindStringSubmatch(name)
+		if submatches == nil {
+			return name, nil
+		}
+	}
+	if r.Template == nil {
+		return r.Pattern.ReplaceAllString(name, r.Replacement), nil
+	}
+
+	ext := path.Ext(name)
+	if folder {
+		ext = ""
+	}
+	data := RenameTemplateData{
+		Name:       name,
+		Stem:       strings.TrimSuffix(name, ext),
+		Ext:        ext,
+		Submatches: submatches,
+		Index:      index,
+	}
+	var sb strings.Builder
+	err := r.Template.Execute(&sb, data)
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+func applyRenameRules(rules []RenameRule, name string, index int64) (string, error) {
+	newName := name
+	for _, r := range rules {
+		var err error
+		newName, err = r.apply(newName, index)
+		if err != nil {
+			return "", err
+		}
+	}
+	if newName == "" || strings.Contains(newName, "/") {
+		return "", fmt.Errorf("%w: %q renamed to %q", ErrInvalidRename, name, newName)
+	}
+
+	return newName, nil
+}
+
+// BulkRename applies the rules, in order, to every file and folder of the torrent with
+// the given hash and returns the resulting renames; when dryRun is set the renames
+// are only computed and not performed.
+// When only folders are renamed and none of them contains another renamed folder, each folder is
+// renamed with core.rename_folder. Otherwise the renames of the folders are applied to the paths
+// of the files they contain, so that all the renames are performed with a single core.rename_files
+// call: the daemon performs the renames asynchronously, thus separate folder renames could conflict
+// with the pending file renames. In that case the daemon does not remove the original folders,
+// which are left empty on disk.
+// The returned operations contain the renamed files, with their final paths, followed by the
+// renamed folders.
+func (c *Client) BulkRename(id string, rules []RenameRule, dryRun bool) ([]RenameOperation, error) {
+	var args rencode.List
+	args.Add(id)
+	args.Add(rencode.NewList("files"))
+
+	rd, err := c.rpcWithDictionaryResult("core.get_torrent_status", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	var s struct {
+		Files []File
+	}
+	err = rd.ToStruct(&s, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := planRenames(s.Files, rules)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return ops, nil
+	}
+
+	if foldersOnly(ops) {
+		for _, op := range ops {
+			if op.Index < 0 {
+				err = c.RenameFolder(id, op.OldPath, op.NewPath)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		return ops, nil
+	}
+
+	fileRenames := map[int64]string{}
+	for _, op := range ops {
+		if op.Index >= 0 {
+			fileRenames[op.Index] = op.NewPath
+		}
+	}
+	if len(fileRenames) != 0 {
+		err = c.RenameFiles(id, fileRenames)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ops, nil
+}
+
+// foldersOnly returns true if the operations only move files with the folders containing them
+// and none of the renamed folders contains another one.
+func foldersOnly(ops []RenameOperation) bool {
+	var folders []string
+	for _, op := range ops {
+		if op.Index >= 0 && path.Base(op.OldPath) != path.Base(op.NewPath) {
+			return false
+		}
+		if op.Index < 0 {
+			folders = append(folders, op.OldPath)
+		}
+	}
+	for _, a := range folders {
+		for _, b := range folders {
+			if a != b && strings.HasPrefix(b, a) {
+				return false
+			}
+		}
+	}
+
+	return len(folders) != 0
+}
+
+// planRenames computes the renames of the files, with the final paths including the renamed folders,
+// followed by the renames of the folders ordered by path.
+func planRenames(files []File, rules []RenameRule) ([]RenameOperation, error) {
+	for i, r := range rules {
+		if r.Pattern == nil && r.Template == nil {
+			return nil, fmt.Errorf("%w: rule %d has neither a pattern nor a template", ErrInvalidRename, i)
+		}
+	}
+
+	folders := map[string]struct{}{}
+	for _, f := range files {
+		dir, _ := path.Split(f.Path)
+		for d := dir; d != ""; d, _ = path.Split(strings.TrimSuffix(d, "/")) {
+			folders[d] = struct{}{}
+		}
+	}
+
+	// parents sort before their subfolders, thus their new path is known first
+	sortedFolders := make([]string, 0, len(folders))
+	for d := range folders {
+		sortedFolders = append(sortedFolders, d)
+	}
+	sort.Strings(sortedFolders)
+
+	var folderOps []RenameOperation
+	newFolders := map[string]string{"": ""}
+	renamedFrom := map[string]string{}
+	for _, d := range sortedFolders {
+		parent, name := path.Split(strings.TrimSuffix(d, "/"))
+		newName, err := applyRenameRules(rules, name, -1)
+		if err != nil {
+			return nil, err
+		}
+		newFolder := newFolders[parent] + newName + "/"
+		if other, ok := renamedFrom[newFolder]; ok {
+			return nil, fmt.Errorf("%w: folders %q and %q both renamed to %q", ErrInvalidRename, other, d, newFolder)
+		}
+		renamedFrom[newFolder] = d
+		newFolders[d] = newFolder
+		if newName != name {
+			folderOps = append(folderOps, RenameOperation{Index: -1, OldPath: d, NewPath: newFolder})
+		}
+	}
+
+	var ops []RenameOperation
+	newPaths := map[string]struct{}{}
+	for _, f := range files {
+		dir, name := path.Split(f.Path)
+		newName, err := applyRenameRules(rules, name, f.Index)
+		if err != nil {
+			return nil, err
+		}
+		newPath := newFolders[dir] + newName
+		if _, ok := newPaths[newPath]; ok {
+			return nil, fmt.Errorf("%w: more than one file renamed to %q", ErrInvalidRename, newPath)
+		}
+		if _, ok := renamedFrom[newPath+"/"]; ok {
+			return nil, fmt.Errorf("%w: file %q renamed to folder %q", ErrInvalidRename, f.Path, newPath)
+		}
+		newPaths[newPath] = struct{}{}
+		if newPath != f.Path {
+			ops = append(ops, RenameOperation{Index: f.Index, OldPath: f.Path, NewPath: newPath})
+		}
+	}
+
+	return append(ops, folderOps...), nil
+}
@@ -0,0 +1,186 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"text/template"
+
+	"github.com/gdm85/go-rencode"
+)
+
+var testFiles = []File{
+	{Index: 0, Path: "Show.S01/Show.S01E01.mkv"},
+	{Index: 1, Path: "Show.S01/Show.S01E02.mkv"},
+	{Index: 2, Path: "Show.S01/Sample/sample.mkv"},
+	{Index: 3, Path: "Show.S01/info.nfo"},
+}
+
+func TestPlanRenames(t *testing.T) {
+	t.Parallel()
+
+	rules := []RenameRule{
+		{
+			Pattern:  regexp.MustCompile(`^Show\.S(\d+)E(\d+)\.mkv$`),
+			Template: template.Must(template.New("").Parse(`Show - {{index .Submatches 1}}x{{index .Submatches 2}}{{.Ext}}`)),
+			Target:   RenameTargetFiles,
+		},
+		{
+			Pattern:     regexp.MustCompile(`\.`),
+			Replacement: " ",
+			Target:      RenameTargetFolders,
+		},
+	}
+
+	ops, err := planRenames(testFiles, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []RenameOperation{
+		{Index: 0, OldPath: "Show.S01/Show.S01E01.mkv", NewPath: "Show S01/Show - 01x01.mkv"},
+		{Index: 1, OldPath: "Show.S01/Show.S01E02.mkv", NewPath: "Show S01/Show - 01x02.mkv"},
+		{Index: 2, OldPath: "Show.S01/Sample/sample.mkv", NewPath: "Show S01/Sample/sample.mkv"},
+		{Index: 3, OldPath: "Show.S01/info.nfo", NewPath: "Show S01/info.nfo"},
+		{Index: -1, OldPath: "Show.S01/", NewPath: "Show S01/"},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("expected %+v, got %+v", expected, ops)
+	}
+}
+
+func TestPlanRenamesConflict(t *testing.T) {
+	t.Parallel()
+
+	rules := []RenameRule{{Pattern: regexp.MustCompile(`E0\d`), Replacement: "E00"}}
+
+	_, err := planRenames(testFiles, rules)
+	if !errors.Is(err, ErrInvalidRename) {
+		t.Errorf("expected an invalid rename error, got %v", err)
+	}
+}
+
+func TestPlanRenamesFolderConflict(t *testing.T) {
+	t.Parallel()
+
+	files := []File{
+		{Index: 0, Path: "Show/CD1/a.mkv"},
+		{Index: 1, Path: "Show/CD2/b.mkv"},
+	}
+	rules := []RenameRule{{Pattern: regexp.MustCompile(`^CD\d$`), Replacement: "Disc", Target: RenameTargetFolders}}
+
+	_, err := planRenames(files, rules)
+	if !errors.Is(err, ErrInvalidRename) {
+		t.Errorf("expected an invalid rename error, got %v", err)
+	}
+}
+
+func TestBulkRenameDryRun(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary("files", testFiles))
+
+	ops, err := c.BulkRename("hash", []RenameRule{{Pattern: regexp.MustCompile(`^sample`), Replacement: "skip"}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].NewPath != "Show.S01/Sample/skip.mkv" {
+		t.Errorf("unexpected renames %+v", ops)
+	}
+	if n := len(conn.sentRequests()); n != 1 {
+		t.Errorf("expected no requests besides status retrieval, got %d", n-1)
+	}
+}
+
+func TestBulkRenameFolder(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary("files", testFiles))
+	conn.addResponse(2, nil)
+
+	rules := []RenameRule{
+		{Pattern: regexp.MustCompile(`^sample`), Replacement: "skip"},
+		{Pattern: regexp.MustCompile(`^Sample$`), Replacement: "Extras", Target: RenameTargetFolders},
+	}
+	_, err := c.BulkRename("hash", rules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the folder rename is applied to the path of the renamed file in the same call
+	if n := len(conn.sentRequests()); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+	method, args, _ := conn.lastMethod()
+	var (
+		id        string
+		filenames rencode.List
+	)
+	err = args.Scan(&id, &filenames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{rencode.NewList(int8(2), []byte("Show.S01/Extras/skip.mkv"))}
+	if method != "core.rename_files" || !reflect.DeepEqual(filenames.Values(), expected) {
+		t.Errorf("unexpected request %s%v", method, filenames.Values())
+	}
+}
+
+func TestPlanRenamesTemplateOnly(t *testing.T) {
+	t.Parallel()
+
+	rules := []RenameRule{{Template: template.Must(template.New("").Parse(`{{printf "%02d" .Index}}{{.Ext}}`)), Target: RenameTargetFiles}}
+	ops, err := planRenames(testFiles[:1], rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].NewPath != "Show.S01/00.mkv" {
+		t.Errorf("unexpected renames %+v", ops)
+	}
+
+	_, err = planRenames(testFiles, []RenameRule{{Replacement: "x"}})
+	if !errors.Is(err, ErrInvalidRename) {
+		t.Errorf("expected an invalid rename error, got %v", err)
+	}
+}
+
+func TestBulkRenameFoldersOnly(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary("files", testFiles))
+	conn.addResponse(2, nil)
+
+	rules := []RenameRule{{Pattern: regexp.MustCompile(`^Sample$`), Replacement: "Extras", Target: RenameTargetFolders}}
+	_, err := c.BulkRename("hash", rules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	method, args, _ := conn.lastMethod()
+	var id, folder, newFolder string
+	err = args.Scan(&id, &folder, &newFolder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "core.rename_folder" || folder != "Show.S01/Sample/" || newFolder != "Show.S01/Extras/" {
+		t.Errorf("unexpected request %s%v", method, args.Values())
+	}
+}
@@ -0,0 +1,187 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// TorrentStatusAs returns the status of the torrent with specified hash, decoded into T.
+// See TorrentsStatusAs for the requirements on T.
+func TorrentStatusAs[T any](dc DelugeClient, hash string) (*T, error) {
+	c, err := clientOf(dc)
+	if err != nil {
+		return nil, err
+	}
+	view, err := newStatusView[T](c.statusExcludeTag())
+	if err != nil {
+		return nil, err
+	}
+
+	var args rencode.List
+	args.Add(hash)
+	args.Add(c.statusKeys(view.keys))
+
+	rd, err := c.rpcWithDictionaryResult("core.get_torrent_status", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeStatusView[T](c, rd, view)
+}
+
+// TorrentsStatusAs returns the status of torrents matching the specified state and list of hashes,
+// decoded into T. Both state and list of hashes are optional.
+// T must be a struct; the requested keys are the snake case names of its exported fields, e.g. TotalSize
+// for "total_size", as with rencode.Dictionary.ToStruct. Unexported fields and fields annotated with
+// `rencode:"-"` are skipped, as well as fields annotated with `rencode:"v2only"` on v1 daemons and with
+// `rencode:"v1only"` on v2 daemons.
+// Every requested key must be returned by the daemon.
+func TorrentsStatusAs[T any](dc DelugeClient, state TorrentState, hashes []string) (map[string]*T, error) {
+	return FilterTorrentsStatusAs[T](dc, &Filter{State: state, IDs: hashes})
+}
+
+// FilterTorrentsStatusAs returns the status of torrents matching the filter, decoded into T.
+// See TorrentsStatusAs for the requirements on T.
+func FilterTorrentsStatusAs[T any](dc DelugeClient, filter *Filter) (map[string]*T, error) {
+	c, err := clientOf(dc)
+	if err != nil {
+		return nil, err
+	}
+	view, err := newStatusView[T](c.statusExcludeTag())
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := c.torrentsStatusDictionaries(filter.toDictionary(), c.statusKeys(view.keys))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*T, len(d))
+	for k, v := range d {
+		s, err := decodeStatusView[T](c, v, view)
+		if err != nil {
+			return nil, fmt.Errorf("torrent %s: %w", k, err)
+		}
+		result[k] = s
+	}
+
+	return result, nil
+}
+
+// clientOf returns the client of a *Client or a *ClientV2.
+func clientOf(dc DelugeClient) (*Client, error) {
+	switch c := dc.(type) {
+	case *Client:
+		return c, nil
+	case *ClientV2:
+		return &c.Client, nil
+	}
+	return nil, fmt.Errorf("unsupported client type %T", dc)
+}
+
+// statusExcludeTag returns the annotation of the struct fields which are not available on the daemon.
+func (c *Client) statusExcludeTag() string {
+	if c.v2daemon {
+		return "v1only"
+	}
+	return c.excludeTag
+}
+
+// statusView describes the fields of T which are decoded from a torrent status.
+type statusView struct {
+	// typ is a struct with only the decoded fields of T, for rencode.Dictionary.ToStruct
+	typ     reflect.Type
+	indexes []int // index in T of each field of typ
+	keys    []StatusKey
+}
+
+// newStatusView returns the view of the exported fields of T, skipping the fields annotated
+// with "-" or excludeTag.
+func newStatusView[T any](excludeTag string) (*statusView, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %v", t)
+	}
+
+	var (
+		view   statusView
+		fields []reflect.StructField
+	)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || hasTag(f, "-") || hasTag(f, excludeTag) {
+			continue
+		}
+		fields = append(fields, reflect.StructField{Name: f.Name, Type: f.Type})
+		view.indexes = append(view.indexes, i)
+		view.keys = append(view.keys, StatusKey(rencode.ToSnakeCase(f.Name)))
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("struct %v has no fields", t)
+	}
+	view.typ = reflect.StructOf(fields)
+
+	return &view, nil
+}
+
+// decodeStatusView decodes a torrent status dictionary into T.
+func decodeStatusView[T any](c *Client, rd rencode.Dictionary, view *statusView) (*T, error) {
+	if !c.v2daemon {
+		// rename the v1 keys requested by statusKeys
+		d, err := rd.Zip()
+		if err != nil {
+			return nil, err
+		}
+		c.normalizeStatusKeys(d)
+		if v, ok := d["save_path"]; ok && view.hasKey(StatusKeyDownloadLocation) {
+			d["download_location"] = v
+			if !view.hasKey(StatusKeySavePath) {
+				delete(d, "save_path")
+			}
+		}
+		rd = rencode.Dictionary{}
+		for k, v := range d {
+			rd.Add(k, v)
+		}
+	}
+
+	dv := reflect.New(view.typ)
+	err := rd.ToStruct(dv.Interface(), c.statusExcludeTag())
+	if err != nil {
+		return nil, err
+	}
+
+	var s T
+	v := reflect.ValueOf(&s).Elem()
+	for i, index := range view.indexes {
+		v.Field(index).Set(dv.Elem().Field(i))
+	}
+
+	return &s, nil
+}
+
+func (view *statusView) hasKey(key StatusKey) bool {
+	for _, k := range view.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
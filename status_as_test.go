@@ -0,0 +1,165 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+type testTorrentView struct {
+	Name             string
+	TotalSize        int64
+	DownloadLocation string `rencode:"v2only"`
+}
+
+func TestTorrentsStatusAs(t *testing.T) {
+	t.Parallel()
+
+	var status rencode.Dictionary
+	status.Add("name", "ubuntu.iso")
+	status.Add("total_size", int16(4096))
+	var torrents rencode.Dictionary
+	torrents.Add(testStatusHash, status)
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, torrents)
+
+	result, err := TorrentsStatusAs[testTorrentView](c, StateUnspecified, []string{testStatusHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := result[testStatusHash]
+	if v == nil || v.Name != "ubuntu.iso" || v.TotalSize != 4096 {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		filter rencode.Dictionary
+		keys   rencode.List
+	)
+	err = args.Scan(&filter, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys.Length() != 2 {
+		t.Errorf("expected v2-only key to be skipped, got %d keys", keys.Length())
+	}
+}
+
+func TestStatusView(t *testing.T) {
+	t.Parallel()
+
+	type view struct {
+		Name       string
+		TotalSize  int64
+		Compact    bool                   `rencode:"v1only"`
+		Extra      map[string]interface{} `rencode:"-"`
+		unexported int
+	}
+
+	v, err := newStatusView[view]("v1only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v.keys, []StatusKey{StatusKeyName, StatusKeyTotalSize}) {
+		t.Errorf("unexpected keys %v", v.keys)
+	}
+
+	_, err = newStatusView[string]("")
+	if err == nil {
+		t.Error("expected error for non-struct type")
+	}
+}
+
+func TestTorrentStatusAsV2(t *testing.T) {
+	t.Parallel()
+
+	type view struct {
+		Name      string
+		TotalSize int64
+		Compact   bool   `rencode:"v1only"`
+		Label     string `rencode:"-"`
+		hidden    string
+	}
+
+	var status rencode.Dictionary
+	status.Add("name", "ubuntu.iso")
+	status.Add("total_size", int16(4096))
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, status)
+
+	v, err := TorrentStatusAs[view](c, testStatusHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "ubuntu.iso" || v.TotalSize != 4096 {
+		t.Errorf("unexpected status %+v", v)
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		hash string
+		keys rencode.List
+	)
+	err = args.Scan(&hash, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys.Length() != 2 {
+		t.Errorf("expected v1-only key to be skipped, got %v", keys.Values())
+	}
+}
+
+func TestTorrentStatusAsV1Keys(t *testing.T) {
+	t.Parallel()
+
+	type view struct {
+		DownloadLocation          string
+		PrioritizeFirstLastPieces bool
+	}
+
+	var status rencode.Dictionary
+	status.Add("save_path", "/downloads")
+	status.Add("prioritize_first_last", true)
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, status)
+
+	v, err := TorrentStatusAs[view](c, testStatusHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.DownloadLocation != "/downloads" || !v.PrioritizeFirstLastPieces {
+		t.Errorf("unexpected status %+v", v)
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		hash string
+		keys rencode.List
+	)
+	err = args.Scan(&hash, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys.Values(), []interface{}{[]byte("save_path"), []byte("prioritize_first_last")}) {
+		t.Errorf("unexpected keys %v", keys.Values())
+	}
+}
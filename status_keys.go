@@ -0,0 +1,167 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"fmt"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// StatusKey is a key of the torrent status, used to select which fields of TorrentStatus are retrieved.
+// Keys without a constant (e.g. plugin keys like "label") can be converted from a string
+// and are stored in TorrentStatus.Extra.
+type StatusKey string
+
+// The keys of each TorrentStatus field.
+const (
+	StatusKeyHash                      StatusKey = "hash"
+	StatusKeyName                      StatusKey = "name"
+	StatusKeyComment                   StatusKey = "comment"
+	StatusKeyCreator                   StatusKey = "creator"
+	StatusKeyOwner                     StatusKey = "owner"
+	StatusKeyMessage                   StatusKey = "message"
+	StatusKeyState                     StatusKey = "state"
+	StatusKeyPaused                    StatusKey = "paused"
+	StatusKeyActiveTime                StatusKey = "active_time"
+	StatusKeySeedingTime               StatusKey = "seeding_time"
+	StatusKeyFinishedTime              StatusKey = "finished_time"
+	StatusKeyCompletedTime             StatusKey = "completed_time"
+	StatusKeyTimeAdded                 StatusKey = "time_added"
+	StatusKeyLastSeenComplete          StatusKey = "last_seen_complete"
+	StatusKeyTimeSinceDownload         StatusKey = "time_since_download"
+	StatusKeyTimeSinceUpload           StatusKey = "time_since_upload"
+	StatusKeyTimeSinceTransfer         StatusKey = "time_since_transfer"
+	StatusKeyDistributedCopies         StatusKey = "distributed_copies"
+	StatusKeyETA                       StatusKey = "eta"
+	StatusKeyProgress                  StatusKey = "progress"
+	StatusKeyRatio                     StatusKey = "ratio"
+	StatusKeySeedsPeersRatio           StatusKey = "seeds_peers_ratio"
+	StatusKeySeedRank                  StatusKey = "seed_rank"
+	StatusKeyIsFinished                StatusKey = "is_finished"
+	StatusKeyIsSeed                    StatusKey = "is_seed"
+	StatusKeySeedMode                  StatusKey = "seed_mode"
+	StatusKeyPrivate                   StatusKey = "private"
+	StatusKeyShared                    StatusKey = "shared"
+	StatusKeySuperSeeding              StatusKey = "super_seeding"
+	StatusKeySequentialDownload        StatusKey = "sequential_download"
+	StatusKeySavePath                  StatusKey = "save_path"
+	StatusKeyDownloadLocation          StatusKey = "download_location"
+	StatusKeyStorageMode               StatusKey = "storage_mode"
+	StatusKeyCompact                   StatusKey = "compact"
+	StatusKeyDownloadPayloadRate       StatusKey = "download_payload_rate"
+	StatusKeyUploadPayloadRate         StatusKey = "upload_payload_rate"
+	StatusKeyNextAnnounce              StatusKey = "next_announce"
+	StatusKeyNumPeers                  StatusKey = "num_peers"
+	StatusKeyNumSeeds                  StatusKey = "num_seeds"
+	StatusKeyNumFiles                  StatusKey = "num_files"
+	StatusKeyNumPieces                 StatusKey = "num_pieces"
+	StatusKeyPieceLength               StatusKey = "piece_length"
+	StatusKeyTotalDone                 StatusKey = "total_done"
+	StatusKeyTotalPeers                StatusKey = "total_peers"
+	StatusKeyTotalSeeds                StatusKey = "total_seeds"
+	StatusKeyTotalSize                 StatusKey = "total_size"
+	StatusKeyTotalWanted               StatusKey = "total_wanted"
+	StatusKeyTotalRemaining            StatusKey = "total_remaining"
+	StatusKeyAllTimeDownload           StatusKey = "all_time_download"
+	StatusKeyTotalUploaded             StatusKey = "total_uploaded"
+	StatusKeyTotalPayloadDownload      StatusKey = "total_payload_download"
+	StatusKeyTotalPayloadUpload        StatusKey = "total_payload_upload"
+	StatusKeyTracker                   StatusKey = "tracker"
+	StatusKeyTrackerHost               StatusKey = "tracker_host"
+	StatusKeyTrackerStatus             StatusKey = "tracker_status"
+	StatusKeyQueue                     StatusKey = "queue"
+	StatusKeyIsAutoManaged             StatusKey = "is_auto_managed"
+	StatusKeyMaxConnections            StatusKey = "max_connections"
+	StatusKeyMaxUploadSlots            StatusKey = "max_upload_slots"
+	StatusKeyMaxDownloadSpeed          StatusKey = "max_download_speed"
+	StatusKeyMaxUploadSpeed            StatusKey = "max_upload_speed"
+	StatusKeyPrioritizeFirstLastPieces StatusKey = "prioritize_first_last_pieces"
+	StatusKeyStopAtRatio               StatusKey = "stop_at_ratio"
+	StatusKeyStopRatio                 StatusKey = "stop_ratio"
+	StatusKeyRemoveAtRatio             StatusKey = "remove_at_ratio"
+	StatusKeyMoveCompleted             StatusKey = "move_completed"
+	StatusKeyMoveCompletedPath         StatusKey = "move_completed_path"
+	StatusKeyFiles                     StatusKey = "files"
+	StatusKeyOrigFiles                 StatusKey = "orig_files"
+	StatusKeyPeers                     StatusKey = "peers"
+	StatusKeyTrackers                  StatusKey = "trackers"
+	StatusKeyFilePriorities            StatusKey = "file_priorities"
+	StatusKeyFileProgress              StatusKey = "file_progress"
+	StatusKeyPieces                    StatusKey = "pieces"
+)
+
+// TorrentStatusKeys returns the status of the torrent with specified hash, with only the fields
+// of the specified keys set; when no key is specified all the fields are set, like TorrentStatus.
+func (c *Client) TorrentStatusKeys(hash string, keys ...StatusKey) (*TorrentStatus, error) {
+	var args rencode.List
+	args.Add(hash)
+	args.Add(c.statusKeys(keys))
+
+	rd, err := c.rpcWithDictionaryResult("core.get_torrent_status", args, rencode.Dictionary{})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.decodeTorrentStatus(rd)
+}
+
+// TorrentsStatusKeys returns the status of torrents matching the specified state and list of hashes,
+// with only the fields of the specified keys set; when no key is specified all the fields are set,
+// like TorrentsStatus.
+func (c *Client) TorrentsStatusKeys(state TorrentState, hashes []string, keys ...StatusKey) (map[string]*TorrentStatus, error) {
+	return c.FilterTorrentsStatus(&Filter{State: state, IDs: hashes}, keys...)
+}
+
+// FilterTorrentsStatus returns the status of torrents matching the filter, with only the fields
+// of the specified keys set; when no key is specified all the fields are set.
+func (c *Client) FilterTorrentsStatus(filter *Filter, keys ...StatusKey) (map[string]*TorrentStatus, error) {
+	d, err := c.torrentsStatusDictionaries(filter.toDictionary(), c.statusKeys(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*TorrentStatus, len(d))
+	for k, v := range d {
+		ts, err := c.decodeTorrentStatus(v)
+		if err != nil {
+			return nil, fmt.Errorf("torrent %s: %w", k, err)
+		}
+		result[k] = ts
+	}
+
+	return result, nil
+}
+
+// statusKeys returns the list of keys to request to the daemon; v2 key names are
+// translated to their v1 equivalent on v1 daemons. No keys are requested when none is specified,
+// in which case the daemon returns all of them.
+func (c *Client) statusKeys(keys []StatusKey) rencode.List {
+	var list rencode.List
+	for _, k := range keys {
+		if !c.v2daemon {
+			switch k {
+			case StatusKeyDownloadLocation:
+				// set from save_path when decoding
+				k = StatusKeySavePath
+			case StatusKeyPrioritizeFirstLastPieces:
+				k = "prioritize_first_last"
+			}
+		}
+		list.Add(string(k))
+	}
+
+	return list
+}
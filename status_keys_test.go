@@ -0,0 +1,77 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+func TestTorrentsStatusKeys(t *testing.T) {
+	t.Parallel()
+
+	var status rencode.Dictionary
+	status.Add("name", "ubuntu.iso")
+	status.Add("state", "Seeding")
+	var torrents rencode.Dictionary
+	torrents.Add(testStatusHash, status)
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, torrents)
+
+	result, err := c.TorrentsStatusKeys(StatePaused, nil, StatusKeyName, StatusKeyState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := result[testStatusHash]
+	if ts == nil || ts.Name != "ubuntu.iso" || ts.State != "Seeding" {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	method, args, _ := conn.lastMethod()
+	var (
+		filter  rencode.Dictionary
+		keyList rencode.List
+	)
+	err = args.Scan(&filter, &keyList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := rencodeListToSlice(keyList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "core.get_torrents_status" || !reflect.DeepEqual(keys, []string{"name", "state"}) {
+		t.Errorf("unexpected request %s %v", method, keys)
+	}
+}
+
+func TestStatusKeysV1(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newMockConnClient(false, 0)
+
+	keys := c.statusKeys([]StatusKey{StatusKeyDownloadLocation, StatusKeyPrioritizeFirstLastPieces, "label"})
+	if !reflect.DeepEqual(keys.Values(), []interface{}{"save_path", "prioritize_first_last", "label"}) {
+		t.Errorf("unexpected v1 keys %v", keys)
+	}
+
+	defaultKeys := c.statusKeys(nil)
+	if defaultKeys.Length() != 0 {
+		t.Error("expected all keys to be requested")
+	}
+}
@@ -0,0 +1,192 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// StatusChanges contains the changes found by a StatusSyncer refresh.
+type StatusChanges struct {
+	Added   []string               // torrents which were not known before the refresh
+	Removed []string               // torrents which are no longer matching the filter
+	Changed map[string][]StatusKey // changed keys of each previously known torrent
+}
+
+// IsEmpty returns true if there are no changes.
+func (sc *StatusChanges) IsEmpty() bool {
+	return len(sc.Added) == 0 && len(sc.Removed) == 0 && len(sc.Changed) == 0
+}
+
+// StatusSyncer keeps a local copy of the status of the torrents matching a filter up to date.
+// On v2 daemons only the changed fields are transferred, using the diff mode of core.get_torrents_status;
+// the daemon keeps track of the previous status per session, thus no other diff mode queries should be
+// made on the same connection. On v1 daemons the full status is fetched and compared locally.
+// A StatusSyncer is not safe for concurrent use.
+type StatusSyncer struct {
+	c      *Client
+	filter rencode.Dictionary
+	keys   rencode.List
+	diff   bool
+
+	raw      map[string]map[string]interface{}
+	torrents map[string]*TorrentStatus
+}
+
+// NewStatusSyncer returns a syncer for the status of torrents matching the filter, with only the fields
+// of the specified keys set; when no key is specified all the fields are set.
+func (c *Client) NewStatusSyncer(filter *Filter, keys ...StatusKey) *StatusSyncer {
+	return &StatusSyncer{
+		c:        c,
+		filter:   filter.toDictionary(),
+		keys:     c.statusKeys(keys),
+		diff:     c.v2daemon,
+		raw:      map[string]map[string]interface{}{},
+		torrents: map[string]*TorrentStatus{},
+	}
+}
+
+// Torrents returns the status of the torrents as of the last refresh, indexed by hash.
+// The returned status values must not be modified.
+func (s *StatusSyncer) Torrents() map[string]*TorrentStatus {
+	result := make(map[string]*TorrentStatus, len(s.torrents))
+	for k, v := range s.torrents {
+		result[k] = v
+	}
+	return result
+}
+
+// Refresh updates the status of the torrents and returns the changes since the previous refresh;
+// the first refresh reports all torrents as added.
+// In diff mode, the full status of the torrents not previously known by the syncer is fetched with
+// a second query.
+func (s *StatusSyncer) Refresh() (*StatusChanges, error) {
+	// the first refresh fetches the full status, as the daemon could have been queried before
+	diff := s.diff && len(s.raw) != 0
+	var kwargs rencode.Dictionary
+	if diff {
+		kwargs.Add("diff", true)
+	}
+
+	d, err := s.c.getTorrentsStatus(s.filter, s.keys, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	if diff {
+		// the daemon tracks the previous status per session and torrent, not per syncer, thus
+		// torrents unknown to this syncer may have been returned as a diff: fetch their full status
+		var unknown []string
+		for hash := range d {
+			if _, ok := s.raw[hash]; !ok {
+				unknown = append(unknown, hash)
+			}
+		}
+		if len(unknown) != 0 {
+			sort.Strings(unknown)
+			var filterDict rencode.Dictionary
+			filterDict.Add("id", sliceToRencodeList(unknown))
+			full, err := s.c.getTorrentsStatus(filterDict, s.keys, rencode.Dictionary{})
+			if err != nil {
+				return nil, err
+			}
+			for _, hash := range unknown {
+				status, ok := full[hash]
+				if !ok {
+					// removed in the meantime
+					delete(d, hash)
+					continue
+				}
+				d[hash] = status
+			}
+		}
+	}
+
+	changes := StatusChanges{Changed: map[string][]StatusKey{}}
+	raw := make(map[string]map[string]interface{}, len(d))
+	for hash, v := range d {
+		status, err := v.Zip()
+		if err != nil {
+			return nil, err
+		}
+		s.c.normalizeStatusKeys(status)
+
+		prev, known := s.raw[hash]
+		if !known {
+			changes.Added = append(changes.Added, hash)
+			raw[hash] = status
+			continue
+		}
+
+		// merge the received values over the previous ones; without diff mode all the values are received
+		merged := make(map[string]interface{}, len(prev))
+		for k, pv := range prev {
+			merged[k] = pv
+		}
+		var changed []StatusKey
+		for k, nv := range status {
+			pv, ok := prev[k]
+			if !ok || !reflect.DeepEqual(pv, nv) {
+				changed = append(changed, StatusKey(k))
+			}
+			merged[k] = nv
+		}
+		raw[hash] = merged
+
+		if len(changed) != 0 {
+			sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+			changes.Changed[hash] = changed
+		}
+	}
+	for hash := range s.raw {
+		if _, ok := raw[hash]; !ok {
+			changes.Removed = append(changes.Removed, hash)
+		}
+	}
+
+	// decode the status of the added and changed torrents
+	torrents := make(map[string]*TorrentStatus, len(raw))
+	for hash, status := range raw {
+		if _, ok := changes.Changed[hash]; !ok {
+			if ts, ok := s.torrents[hash]; ok {
+				torrents[hash] = ts
+				continue
+			}
+		}
+
+		// the decoder consumes the keys, thus a copy is used
+		d := make(map[string]interface{}, len(status))
+		for k, v := range status {
+			d[k] = v
+		}
+		ts, err := s.c.decodeTorrentStatusMap(d)
+		if err != nil {
+			return nil, fmt.Errorf("torrent %s: %w", hash, err)
+		}
+		torrents[hash] = ts
+	}
+
+	s.raw = raw
+	s.torrents = torrents
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+
+	return &changes, nil
+}
@@ -0,0 +1,177 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+const testStatusHash2 = "d5b5a2ab8b5f8a0bb2d1bfb4e7ea3cf26c2a7d3b"
+
+func TestStatusSyncerDiff(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(
+		testStatusHash, testStatusDictionary("name", "a", "progress", float32(10)),
+		testStatusHash2, testStatusDictionary("name", "b", "progress", float32(100)),
+	))
+	// only the changed fields are returned in diff mode
+	conn.addResponse(2, testStatusDictionary(
+		testStatusHash, testStatusDictionary("progress", float32(20)),
+	))
+
+	s := c.NewStatusSyncer(nil, StatusKeyName, StatusKeyProgress)
+	changes, err := s.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changes.Added, []string{testStatusHash, testStatusHash2}) {
+		t.Errorf("unexpected added torrents %v", changes.Added)
+	}
+
+	changes, err = s.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Added) != 0 || !reflect.DeepEqual(changes.Removed, []string{testStatusHash2}) {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+	if !reflect.DeepEqual(changes.Changed, map[string][]StatusKey{testStatusHash: {StatusKeyProgress}}) {
+		t.Errorf("unexpected changed keys %v", changes.Changed)
+	}
+
+	torrents := s.Torrents()
+	ts := torrents[testStatusHash]
+	if len(torrents) != 1 || ts.Name != "a" || ts.Progress != 20 {
+		t.Errorf("unexpected torrents %v", torrents)
+	}
+
+	_, _, kwargs := conn.lastMethod()
+	m, err := kwargs.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["diff"] != true {
+		t.Errorf("expected diff mode, got %v", m)
+	}
+}
+
+func TestStatusSyncerV1(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, testStatusDictionary(
+		testStatusHash, testStatusDictionary("name", "a", "state", "Downloading"),
+	))
+	conn.addResponse(2, testStatusDictionary(
+		testStatusHash, testStatusDictionary("name", "a", "state", "Seeding"),
+	))
+	conn.addResponse(3, testStatusDictionary(
+		testStatusHash, testStatusDictionary("name", "a", "state", "Seeding"),
+	))
+
+	s := c.NewStatusSyncer(nil, StatusKeyName, StatusKeyState)
+	for i := 0; i < 3; i++ {
+		changes, err := s.Refresh()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch i {
+		case 1:
+			if !reflect.DeepEqual(changes.Changed, map[string][]StatusKey{testStatusHash: {StatusKeyState}}) {
+				t.Errorf("unexpected changed keys %v", changes.Changed)
+			}
+		case 2:
+			if !changes.IsEmpty() {
+				t.Errorf("expected no changes, got %+v", changes)
+			}
+		}
+	}
+
+	if s.Torrents()[testStatusHash].State != "Seeding" {
+		t.Errorf("unexpected state %q", s.Torrents()[testStatusHash].State)
+	}
+
+	_, _, kwargs := conn.lastMethod()
+	if kwargs.Length() != 0 {
+		t.Error("expected no diff mode on v1")
+	}
+}
+
+func TestStatusSyncerDiffUnknownTorrent(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(
+		testStatusHash, testStatusDictionary("name", "a", "progress", float32(10)),
+	))
+	// the daemon returns a diff for a torrent which was reported before on this session,
+	// like one matching the filter again
+	conn.addResponse(2, testStatusDictionary(
+		testStatusHash2, testStatusDictionary("progress", float32(30)),
+	))
+	conn.addResponse(3, testStatusDictionary(
+		testStatusHash2, testStatusDictionary("name", "b", "progress", float32(30)),
+	))
+
+	s := c.NewStatusSyncer(nil, StatusKeyName, StatusKeyProgress)
+	_, err := s.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changes.Added, []string{testStatusHash2}) ||
+		!reflect.DeepEqual(changes.Removed, []string{testStatusHash}) {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+	ts := s.Torrents()[testStatusHash2]
+	if ts == nil || ts.Name != "b" || ts.Progress != 30 {
+		t.Errorf("unexpected torrent status %+v", ts)
+	}
+
+	requests := conn.sentRequests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	for i, diff := range []bool{false, true, false} {
+		var kwargs rencode.Dictionary
+		kwargs, _ = requests[i].Values()[3].(rencode.Dictionary)
+		if (kwargs.Length() != 0) != diff {
+			t.Errorf("request %d: unexpected kwargs %v", i, kwargs.Keys())
+		}
+	}
+
+	_, args, _ := conn.lastMethod()
+	var filterDict rencode.Dictionary
+	err = args.Scan(&filterDict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := filterDict.Zip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, ok := m["id"].(rencode.List)
+	if !ok || !reflect.DeepEqual(ids.Values(), []interface{}{[]byte(testStatusHash2)}) {
+		t.Errorf("unexpected filter %v", m)
+	}
+}
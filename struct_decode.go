@@ -0,0 +1,157 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// hasTag returns true if the struct field has the specified rencode annotation tag.
+func hasTag(f reflect.StructField, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	rencodeTag, ok := f.Tag.Lookup("rencode")
+	if !ok {
+		return false
+	}
+	for _, t := range strings.Split(rencodeTag, ",") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeStruct assigns the values of a zipped dictionary to the fields of the struct pointed by dest,
+// matching the keys with the snake case of the field names.
+// Unlike rencode.Dictionary.ToStruct, missing keys and nil values leave the fields unchanged;
+// fields annotated with excludeTag or "-" are skipped.
+// The assigned keys are removed from the dictionary, so that the unknown keys are left in it.
+func decodeStruct(d map[string]interface{}, dest interface{}, excludeTag string) error {
+	v := reflect.ValueOf(dest).Elem()
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		if hasTag(t.Field(i), "-") || hasTag(t.Field(i), excludeTag) {
+			continue
+		}
+		name := rencode.ToSnakeCase(t.Field(i).Name)
+		value, ok := d[name]
+		if !ok {
+			continue
+		}
+		delete(d, name)
+		if value == nil {
+			continue
+		}
+
+		err := assignValue(value, v.Field(i), excludeTag)
+		if err != nil {
+			return fmt.Errorf("field %q: %v", t.Field(i).Name, err)
+		}
+	}
+
+	return nil
+}
+
+// assignValue assigns a decoded rencode value to the destination, recursively;
+// integers are accepted for floats and floats are truncated for integers, since the daemon
+// does not enforce the type of numbers.
+func assignValue(value interface{}, dest reflect.Value, excludeTag string) error {
+	l := rencode.NewList(value)
+	switch dest.Kind() {
+	case reflect.Ptr:
+		p := reflect.New(dest.Type().Elem())
+		err := assignValue(value, p.Elem(), excludeTag)
+		if err != nil {
+			return err
+		}
+		dest.Set(p)
+	case reflect.Slice:
+		var list rencode.List
+		err := l.Scan(&list)
+		if err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(dest.Type(), list.Length(), list.Length())
+		for i, item := range list.Values() {
+			err = assignValue(item, s.Index(i), excludeTag)
+			if err != nil {
+				return fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		dest.Set(s)
+	case reflect.Struct:
+		var dict rencode.Dictionary
+		err := l.Scan(&dict)
+		if err != nil {
+			return err
+		}
+		d, err := dict.Zip()
+		if err != nil {
+			return err
+		}
+		return decodeStruct(d, dest.Addr().Interface(), excludeTag)
+	case reflect.Interface:
+		dest.Set(reflect.ValueOf(value))
+	case reflect.Bool:
+		var b bool
+		err := l.Scan(&b)
+		if err != nil {
+			return err
+		}
+		dest.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		err := l.Scan(&i)
+		if err != nil {
+			var f float64
+			l = rencode.NewList(value)
+			if l.Scan(&f) != nil {
+				return err
+			}
+			i = int64(f)
+		}
+		dest.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		err := l.Scan(&f)
+		if err != nil {
+			var i int64
+			l = rencode.NewList(value)
+			if l.Scan(&i) != nil {
+				return err
+			}
+			f = float64(i)
+		}
+		dest.SetFloat(f)
+	case reflect.String:
+		var s string
+		err := l.Scan(&s)
+		if err != nil {
+			return err
+		}
+		dest.SetString(s)
+	default:
+		return fmt.Errorf("unsupported type %v", dest.Type())
+	}
+
+	return nil
+}
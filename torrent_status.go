@@ -15,51 +15,182 @@
 package delugeclient
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/gdm85/go-rencode"
 )
 
-// TorrentStatus contains commonly used torrent attributes, as reported
-// by the deluge server.
-// The full list of potentially available attributes can be found here:
-// https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/torrent.py#L1033-L1143
-// If a new field is added to this struct it should also be added to the statusKeys map.
+// TorrentStatus contains the torrent attributes, as reported by the deluge server.
+// The full list of available attributes can be found here:
+// v2: https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/torrent.py#L1033-L1143
+// v1: https://github.com/deluge-torrent/deluge/blob/1.3-stable/deluge/core/torrent.py#L590-L700
+// If a new field is added to this struct it should also get a StatusKey constant.
 type TorrentStatus struct {
-	ActiveTime          int64
-	CompletedTime       int64   `rencode:"v2only"`
-	TimeAdded           float32 // most times an integer
-	LastSeenComplete    float32 `rencode:"v2only"`
-	DistributedCopies   float32
-	ETA                 float32 // most times an integer
-	Progress            float32 // max is 100
-	Ratio               float32
-	IsFinished          bool
-	IsSeed              bool
-	Private             bool
-	SavePath            string
-	DownloadLocation    string `rencode:"v2only"`
-	DownloadPayloadRate int64
-	Name                string
-	NextAnnounce        int64
-	NumPeers            int64
-	NumPieces           int64
-	NumSeeds            int64
-	PieceLength         int64
-	SeedingTime         int64
-	State               string
-	TotalDone           int64
-	TotalPeers          int64
-	TotalSeeds          int64
-	TotalSize           int64
-	TrackerHost         string
-	TrackerStatus       string
-	UploadPayloadRate   int64
+	Hash                 string
+	Name                 string
+	Comment              string
+	Creator              string `rencode:"v2only"`
+	Owner                string
+	Message              string
+	State                TorrentState
+	Paused               bool
+	ActiveTime           int64   // in seconds
+	SeedingTime          int64   // in seconds
+	FinishedTime         int64   `rencode:"v2only"` // total seconds spent finished
+	CompletedTime        int64   `rencode:"v2only"` // Unix time, 0 if never completed
+	TimeAdded            float64 // Unix time; v1 transfers it as a 32-bit float, thus with a precision of minutes
+	LastSeenComplete     int64   `rencode:"v2only"` // Unix time, 0 if never seen complete
+	TimeSinceDownload    int64   `rencode:"v2only"` // in seconds, -1 if never downloaded
+	TimeSinceUpload      int64   `rencode:"v2only"` // in seconds, -1 if never uploaded
+	TimeSinceTransfer    int64   `rencode:"v2only"` // in seconds, -1 if never transferred
+	DistributedCopies    float32
+	ETA                  int64   // in seconds, 0 if unknown and -1 if more than a year
+	Progress             float32 // max is 100
+	Ratio                float32
+	SeedsPeersRatio      float32
+	SeedRank             int64
+	IsFinished           bool
+	IsSeed               bool
+	SeedMode             bool `rencode:"v2only"`
+	Private              bool
+	Shared               bool `rencode:"v2only"`
+	SuperSeeding         bool `rencode:"v2only"`
+	SequentialDownload   bool `rencode:"v2only"`
+	SavePath             string
+	DownloadLocation     string `rencode:"v2only"`
+	StorageMode          string `rencode:"v2only"` // "sparse" or "allocate"
+	Compact              bool   `rencode:"v1only"` // compact allocation
+	DownloadPayloadRate  int64
+	UploadPayloadRate    int64
+	NextAnnounce         int64 // in seconds
+	NumPeers             int64
+	NumSeeds             int64
+	NumFiles             int64
+	NumPieces            int64
+	PieceLength          int64
+	TotalDone            int64
+	TotalPeers           int64
+	TotalSeeds           int64
+	TotalSize            int64
+	TotalWanted          int64
+	TotalRemaining       int64 `rencode:"v2only"`
+	AllTimeDownload      int64
+	TotalUploaded        int64
+	TotalPayloadDownload int64
+	TotalPayloadUpload   int64
+	Tracker              string
+	TrackerHost          string
+	TrackerStatus        string
+	Queue                int64 // position in the queue, -1 when not queued
+	IsAutoManaged        bool
+
+	// per-torrent options
+	MaxConnections            int64
+	MaxUploadSlots            int64
+	MaxDownloadSpeed          float32
+	MaxUploadSpeed            float32
+	PrioritizeFirstLastPieces bool // reported as prioritize_first_last on v1
+	StopAtRatio               bool
+	StopRatio                 float32
+	RemoveAtRatio             bool
+	MoveCompleted             bool
+	MoveCompletedPath         string
 
 	Files          []File
+	OrigFiles      []File `rencode:"v2only"`
 	Peers          []Peer
-	FilePriorities []int64
+	Trackers       []Tracker
+	FilePriorities []FilePriority // v1 values are mapped to the closest priority
 	FileProgress   []float32
+	Pieces         []int64 `rencode:"v2only"` // state of each piece, nil when metadata is not available
+
+	// Extra contains the keys returned by the daemon which do not map to a field, e.g. keys
+	// added by newer daemons or by plugins
+	Extra map[string]interface{} `rencode:"-"`
+}
+
+// AddedAt returns the time when the torrent was added.
+func (ts *TorrentStatus) AddedAt() time.Time {
+	if ts.TimeAdded <= 0 {
+		return time.Time{}
+	}
+	sec, frac := math.Modf(ts.TimeAdded)
+	return time.Unix(int64(sec), int64(frac*1e9))
 }
 
+// CompletedAt returns the time when the torrent was completed, or the zero time if it never was.
+func (ts *TorrentStatus) CompletedAt() time.Time {
+	return unixTime(ts.CompletedTime)
+}
+
+// LastSeenCompleteAt returns the last time when the torrent was seen complete by this client or
+// any of its peers, or the zero time if it never was.
+func (ts *TorrentStatus) LastSeenCompleteAt() time.Time {
+	return unixTime(ts.LastSeenComplete)
+}
+
+// ActiveDuration returns the time the torrent has been active.
+func (ts *TorrentStatus) ActiveDuration() time.Duration {
+	return seconds(ts.ActiveTime)
+}
+
+// SeedingDuration returns the time the torrent has been seeding.
+func (ts *TorrentStatus) SeedingDuration() time.Duration {
+	return seconds(ts.SeedingTime)
+}
+
+// FinishedDuration returns the total time the torrent has spent in the finished state,
+// which is not the time since it finished downloading.
+func (ts *TorrentStatus) FinishedDuration() time.Duration {
+	return seconds(ts.FinishedTime)
+}
+
+// ETADuration returns the estimated time to completion, or zero if unknown; an estimation of
+// more than a year is returned as zero as well, ETA is -1 in that case.
+func (ts *TorrentStatus) ETADuration() time.Duration {
+	return seconds(ts.ETA)
+}
+
+// NextAnnounceDuration returns the time until the next tracker announce.
+func (ts *TorrentStatus) NextAnnounceDuration() time.Duration {
+	return seconds(ts.NextAnnounce)
+}
+
+// SinceDownload returns the time since the last download, or zero if there was none.
+func (ts *TorrentStatus) SinceDownload() time.Duration {
+	return seconds(ts.TimeSinceDownload)
+}
+
+// SinceUpload returns the time since the last upload, or zero if there was none.
+func (ts *TorrentStatus) SinceUpload() time.Duration {
+	return seconds(ts.TimeSinceUpload)
+}
+
+// SinceTransfer returns the time since the last download or upload, or zero if there was none.
+func (ts *TorrentStatus) SinceTransfer() time.Duration {
+	return seconds(ts.TimeSinceTransfer)
+}
+
+// unixTime returns the time of a Unix timestamp; the "never" and "unknown" values map to the zero time.
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// seconds returns the duration of a number of seconds; the "never" and "unknown" values map to zero.
+func seconds(sec int64) time.Duration {
+	if sec <= 0 {
+		return 0
+	}
+	return time.Duration(sec) * time.Second
+}
+
+// TorrentState is the state of a torrent, or the state to filter torrents by.
 type TorrentState string
 
 // See all defined torrent states here: https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/common.py#L70-L78
@@ -77,70 +208,82 @@ const (
 	StateMoving      TorrentState = "Moving"
 )
 
-var (
-	// each of the available fields in a torrent status
-	// fields differ from v1/v2
-	// See current list at https://github.com/deluge-torrent/deluge/blob/deluge-2.0.3/deluge/core/torrent.py#L1033-L1143
-	commonStatusKeys = []interface{}{
-		"state",
-		"tracker_host",
-		"tracker_status",
-		"next_announce",
-		"name",
-		"total_size",
-		"progress",
-		"num_seeds",
-		"total_seeds",
-		"num_peers",
-		"total_peers",
-		"eta",
-		"download_payload_rate",
-		"upload_payload_rate",
-		"ratio",
-		"distributed_copies",
-		"num_pieces",
-		"piece_length",
-		"total_done",
-		"files",
-		"file_priorities",
-		"file_progress",
-		"peers",
-		"is_seed",
-		"is_finished",
-		"active_time",
-		"seeding_time",
-		"time_added",
-		"private",
-		"save_path", // supported by both v1 and v2
-	}
-	statusKeysV1 = rencode.NewList(commonStatusKeys...)
-	statusKeysV2 = rencode.NewList(append(commonStatusKeys[:],
-		"download_location",  // v2-only; v1 will not return it if queried to do so
-		"completed_time",     // v2-only
-		"last_seen_complete", // v2-only
-	)...)
-)
+// ErrUnknownTorrentState is returned when parsing or decoding a state which is not a torrent state.
+var ErrUnknownTorrentState = errors.New("unknown torrent state")
 
-// TorrentStatus returns the status of the torrent with specified hash.
-func (c *Client) TorrentStatus(hash string) (*TorrentStatus, error) {
-	var args rencode.List
-	args.Add(hash)
-	if !c.v2daemon {
-		args.Add(statusKeysV1)
-	} else {
-		args.Add(statusKeysV2)
+// ParseTorrentState returns the torrent state of s; the special 'Active' state is not a torrent state,
+// as it can only be used for filtering.
+func ParseTorrentState(s string) (TorrentState, error) {
+	state := TorrentState(s)
+	if !state.Valid() {
+		return StateUnspecified, fmt.Errorf("%w: %q", ErrUnknownTorrentState, s)
 	}
+	return state, nil
+}
+
+// Valid returns true if the state is one of the states a torrent can be in.
+func (s TorrentState) Valid() bool {
+	switch s {
+	case StateAllocating, StateChecking, StateDownloading, StateSeeding, StatePaused, StateError, StateQueued, StateMoving:
+		return true
+	}
+	return false
+}
 
-	rd, err := c.rpcWithDictionaryResult("core.get_torrent_status", args, rencode.Dictionary{})
+// IsActive returns true if the torrent is downloading or uploading data, which is what
+// the special 'Active' state selects when filtering.
+func (ts *TorrentStatus) IsActive() bool {
+	return ts.DownloadPayloadRate > 0 || ts.UploadPayloadRate > 0
+}
+
+// IsComplete returns true if all the wanted data of the torrent has been downloaded.
+func (ts *TorrentStatus) IsComplete() bool {
+	return ts.IsFinished || ts.State == StateSeeding
+}
+
+// IsErrored returns true if the torrent is in the error state; the error is described by Message.
+func (ts *TorrentStatus) IsErrored() bool {
+	return ts.State == StateError
+}
+
+// IsTransferring returns true if the torrent is in a downloading or seeding state, regardless
+// of whether data is currently being transferred.
+func (ts *TorrentStatus) IsTransferring() bool {
+	return ts.State == StateDownloading || ts.State == StateSeeding
+}
+
+// decodeTorrentStatus decodes a torrent status dictionary; unknown keys are stored in the Extra map.
+// An error wrapping ErrUnknownTorrentState is returned if the state is not a known torrent state.
+func (c *Client) decodeTorrentStatus(rd rencode.Dictionary) (*TorrentStatus, error) {
+	d, err := rd.Zip()
 	if err != nil {
 		return nil, err
 	}
 
+	return c.decodeTorrentStatusMap(d)
+}
+
+// decodeTorrentStatusMap decodes a zipped torrent status dictionary; the assigned keys are removed from it.
+func (c *Client) decodeTorrentStatusMap(d map[string]interface{}) (*TorrentStatus, error) {
+	c.normalizeStatusKeys(d)
+
 	var ts TorrentStatus
-	err = rd.ToStruct(&ts, c.excludeTag)
+	err := decodeStruct(d, &ts, c.excludeTag)
 	if err != nil {
 		return nil, err
 	}
+	if len(d) != 0 {
+		ts.Extra = d
+	}
+	if ts.State != StateUnspecified {
+		_, err = ParseTorrentState(string(ts.State))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, p := range ts.FilePriorities {
+		ts.FilePriorities[i] = filePriorityFromWire(int64(p), c.v2daemon)
+	}
 
 	// on v2 both fields SavePath and DownloadLocation are already set to the correct values
 	if !c.v2daemon {
@@ -151,25 +294,41 @@ func (c *Client) TorrentStatus(hash string) (*TorrentStatus, error) {
 	return &ts, nil
 }
 
+// normalizeStatusKeys renames the v1 keys of a zipped torrent status dictionary to their v2 name.
+func (c *Client) normalizeStatusKeys(d map[string]interface{}) {
+	if c.v2daemon {
+		return
+	}
+	if v, ok := d["prioritize_first_last"]; ok {
+		d["prioritize_first_last_pieces"] = v
+		delete(d, "prioritize_first_last")
+	}
+}
+
+// TorrentStatus returns the status of the torrent with specified hash.
+func (c *Client) TorrentStatus(hash string) (*TorrentStatus, error) {
+	return c.TorrentStatusKeys(hash)
+}
+
 // TorrentsStatus returns the status of torrents matching the specified state and list of hashes.
 // Both state and list of hashes are optional.
 func (c *Client) TorrentsStatus(state TorrentState, hashes []string) (map[string]*TorrentStatus, error) {
+	return c.TorrentsStatusKeys(state, hashes)
+}
+
+// torrentsStatusDictionaries returns the raw status dictionary of each torrent matching the filter,
+// with only the specified keys.
+func (c *Client) torrentsStatusDictionaries(filterDict rencode.Dictionary, keys rencode.List) (map[string]rencode.Dictionary, error) {
+	return c.getTorrentsStatus(filterDict, keys, rencode.Dictionary{})
+}
+
+// getTorrentsStatus calls core.get_torrents_status with the specified keyword arguments.
+func (c *Client) getTorrentsStatus(filterDict rencode.Dictionary, keys rencode.List, kwargs rencode.Dictionary) (map[string]rencode.Dictionary, error) {
 	var args rencode.List
-	var filterDict rencode.Dictionary
-	if len(hashes) != 0 {
-		filterDict.Add("id", sliceToRencodeList(hashes))
-	}
-	if state != StateUnspecified {
-		filterDict.Add("state", string(state))
-	}
 	args.Add(filterDict)
-	if !c.v2daemon {
-		args.Add(statusKeysV1)
-	} else {
-		args.Add(statusKeysV2)
-	}
+	args.Add(keys)
 
-	rd, err := c.rpcWithDictionaryResult("core.get_torrents_status", args, rencode.Dictionary{})
+	rd, err := c.rpcWithDictionaryResult("core.get_torrents_status", args, kwargs)
 	if err != nil {
 		return nil, err
 	}
@@ -179,26 +338,13 @@ func (c *Client) TorrentsStatus(state TorrentState, hashes []string) (map[string
 		return nil, err
 	}
 
-	result := map[string]*TorrentStatus{}
+	result := make(map[string]rencode.Dictionary, len(d))
 	for k, rv := range d {
 		v, ok := rv.(rencode.Dictionary)
 		if !ok {
 			return nil, ErrInvalidDictionaryResponse
 		}
-
-		var ts TorrentStatus
-		err = v.ToStruct(&ts, c.excludeTag)
-		if err != nil {
-			return nil, err
-		}
-
-		// on v2 both fields SavePath and DownloadLocation are already set to the correct values
-		if !c.v2daemon {
-			// on v1 be forward-compatible with v2
-			ts.DownloadLocation = ts.SavePath
-		}
-
-		result[k] = &ts
+		result[k] = v
 	}
 
 	return result, nil
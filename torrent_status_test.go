@@ -0,0 +1,210 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdm85/go-rencode"
+)
+
+const testStatusHash = "c1939ca413b9afcc34ea0cf3c128574e93ff6cb0"
+
+func TestTorrentStatusExtra(t *testing.T) {
+	t.Parallel()
+
+	var tracker rencode.Dictionary
+	tracker.Add("url", "udp://tracker.example.org:1337/announce")
+	tracker.Add("tier", int8(0))
+	tracker.Add("fails", int8(2)) // additional libtorrent keys are ignored
+
+	var status rencode.Dictionary
+	status.Add("hash", testStatusHash)
+	status.Add("comment", "a comment")
+	status.Add("max_download_speed", int8(-1))
+	status.Add("stop_ratio", float32(2))
+	status.Add("storage_mode", "sparse")
+	status.Add("trackers", rencode.NewList(tracker))
+	status.Add("pieces", nil)
+	status.Add("label", "movies") // not a TorrentStatus field
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, status)
+
+	ts, err := c.TorrentStatus(testStatusHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, args, _ := conn.lastMethod()
+	var (
+		hash string
+		keys rencode.List
+	)
+	err = args.Scan(&hash, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys.Length() != 0 {
+		t.Errorf("expected all keys to be requested, got %v", keys.Values())
+	}
+
+	if ts.Hash != testStatusHash || ts.Comment != "a comment" || ts.StorageMode != "sparse" {
+		t.Errorf("unexpected status %+v", ts)
+	}
+	if ts.MaxDownloadSpeed != -1 || ts.StopRatio != 2 {
+		t.Errorf("unexpected limits %v %v", ts.MaxDownloadSpeed, ts.StopRatio)
+	}
+	if len(ts.Trackers) != 1 || ts.Trackers[0].URL != "udp://tracker.example.org:1337/announce" {
+		t.Errorf("unexpected trackers %v", ts.Trackers)
+	}
+	if ts.Pieces != nil {
+		t.Errorf("expected no pieces, got %v", ts.Pieces)
+	}
+	if len(ts.Extra) != 1 {
+		t.Fatalf("expected 1 extra key, got %v", ts.Extra)
+	}
+	if label, ok := ts.Extra["label"].([]byte); !ok || string(label) != "movies" {
+		t.Errorf("unexpected extra label %v", ts.Extra["label"])
+	}
+}
+
+func TestTorrentStatusV1(t *testing.T) {
+	t.Parallel()
+
+	var status rencode.Dictionary
+	status.Add("save_path", "/downloads")
+	status.Add("prioritize_first_last", true)
+	status.Add("compact", false)
+
+	c, conn := newMockConnClient(false, 0)
+	conn.addResponse(1, status)
+
+	ts, err := c.TorrentStatus(testStatusHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ts.PrioritizeFirstLastPieces {
+		t.Error("expected prioritize_first_last to be decoded")
+	}
+	if ts.DownloadLocation != "/downloads" {
+		t.Errorf("unexpected download location %q", ts.DownloadLocation)
+	}
+	if ts.Extra != nil {
+		t.Errorf("unexpected extra keys %v", ts.Extra)
+	}
+
+	_, args, _ := conn.lastMethod()
+	var (
+		hash string
+		keys rencode.List
+	)
+	err = args.Scan(&hash, &keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys.Length() != 0 {
+		t.Errorf("expected all keys to be requested, got %d keys", keys.Length())
+	}
+}
+
+func TestTorrentStatusTimes(t *testing.T) {
+	t.Parallel()
+
+	var status rencode.Dictionary
+	status.Add("time_added", int32(1700000000))
+	status.Add("completed_time", int8(0))
+	status.Add("last_seen_complete", int32(1700000123))
+	status.Add("eta", float32(-1))
+	status.Add("seeding_time", int16(3600))
+	status.Add("time_since_download", int8(-1))
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, status)
+
+	ts, err := c.TorrentStatus(testStatusHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.TimeAdded != 1700000000 || !ts.AddedAt().Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected added time %v", ts.TimeAdded)
+	}
+	if !ts.CompletedAt().IsZero() {
+		t.Errorf("expected zero completed time, got %v", ts.CompletedAt())
+	}
+	if ts.LastSeenCompleteAt().Unix() != 1700000123 {
+		t.Errorf("unexpected last seen complete time %v", ts.LastSeenCompleteAt())
+	}
+	if ts.ETA != -1 || ts.ETADuration() != 0 {
+		t.Errorf("unexpected ETA %d", ts.ETA)
+	}
+	if ts.SeedingDuration() != time.Hour {
+		t.Errorf("unexpected seeding duration %v", ts.SeedingDuration())
+	}
+	if ts.SinceDownload() != 0 {
+		t.Errorf("unexpected time since download %v", ts.SinceDownload())
+	}
+}
+
+func TestParseTorrentState(t *testing.T) {
+	t.Parallel()
+
+	state, err := ParseTorrentState("Seeding")
+	if err != nil || state != StateSeeding {
+		t.Errorf("unexpected state %q: %v", state, err)
+	}
+
+	for _, s := range []string{"Active", "", "Stalled"} {
+		_, err = ParseTorrentState(s)
+		if !errors.Is(err, ErrUnknownTorrentState) {
+			t.Errorf("expected unknown state error for %q, got %v", s, err)
+		}
+	}
+}
+
+func TestTorrentStatusPredicates(t *testing.T) {
+	t.Parallel()
+
+	paused := TorrentStatus{State: StatePaused, IsFinished: true}
+	if paused.IsActive() || !paused.IsComplete() || paused.IsErrored() || paused.IsTransferring() {
+		t.Errorf("unexpected predicates for %+v", paused)
+	}
+
+	downloading := TorrentStatus{State: StateDownloading, DownloadPayloadRate: 1024}
+	if !downloading.IsActive() || downloading.IsComplete() || !downloading.IsTransferring() {
+		t.Errorf("unexpected predicates for %+v", downloading)
+	}
+
+	errored := TorrentStatus{State: StateError}
+	if !errored.IsErrored() || errored.IsTransferring() {
+		t.Errorf("unexpected predicates for %+v", errored)
+	}
+}
+
+func TestTorrentStatusUnknownState(t *testing.T) {
+	t.Parallel()
+
+	var status rencode.Dictionary
+	status.Add("state", "Hibernating")
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, status)
+
+	_, err := c.TorrentStatus(testStatusHash)
+	if !errors.Is(err, ErrUnknownTorrentState) {
+		t.Errorf("expected unknown state error, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// ErrTorrentNotFound is returned when a torrent is not found in the session.
+var ErrTorrentNotFound = errors.New("torrent not found")
+
+// Tracker is a tracker of a torrent; trackers with a lower tier are tried first.
+type Tracker struct {
+	URL  string
+	Tier int64
+}
+
+// GetTorrentsTrackers returns the trackers of each of the specified torrents, sorted by tier;
+// torrents which are not found are not in the result.
+func (c *Client) GetTorrentsTrackers(ids []string) (map[string][]Tracker, error) {
+	if len(ids) == 0 {
+		return map[string][]Tracker{}, nil
+	}
+
+	status, err := c.FilterTorrentsStatus(&Filter{IDs: ids}, StatusKeyTrackers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]Tracker, len(status))
+	for id, ts := range status {
+		trackers := ts.Trackers
+		if trackers == nil {
+			trackers = []Tracker{}
+		}
+		sortTrackers(trackers)
+		result[id] = trackers
+	}
+
+	return result, nil
+}
+
+// SetTorrentsTrackers replaces the trackers of each of the specified torrents with the complete list of trackers.
+// The failures of single torrents are returned as torrent errors.
+func (c *Client) SetTorrentsTrackers(ids []string, trackers []Tracker) ([]TorrentError, error) {
+	var torrentErrors []TorrentError
+	for _, id := range ids {
+		err := c.setTorrentTrackers(id, trackers)
+		if err != nil {
+			var rpcErr RPCError
+			if !errors.As(err, &rpcErr) {
+				return torrentErrors, err
+			}
+			torrentErrors = append(torrentErrors, TorrentError{ID: id, Message: rpcErr.ExceptionMessage})
+		}
+	}
+
+	return torrentErrors, nil
+}
+
+// AddTorrentsTrackers adds the trackers to each of the specified torrents, keeping their current trackers;
+// the tier of trackers which are already present is updated.
+func (c *Client) AddTorrentsTrackers(ids []string, trackers []Tracker) ([]TorrentError, error) {
+	return c.updateTorrentsTrackers(ids, func(current []Tracker) ([]Tracker, error) {
+		for _, t := range trackers {
+			i := trackerIndex(current, t.URL)
+			if i == -1 {
+				current = append(current, t)
+				continue
+			}
+			current[i].Tier = t.Tier
+		}
+		return current, nil
+	})
+}
+
+// RemoveTorrentsTrackers removes the trackers with the specified URLs from each of the specified torrents;
+// a torrent error is returned for torrents which do not have one of the trackers.
+func (c *Client) RemoveTorrentsTrackers(ids []string, urls []string) ([]TorrentError, error) {
+	return c.updateTorrentsTrackers(ids, func(current []Tracker) ([]Tracker, error) {
+		for _, url := range urls {
+			i := trackerIndex(current, url)
+			if i == -1 {
+				return nil, fmt.Errorf("tracker %q not found", url)
+			}
+			current = append(current[:i], current[i+1:]...)
+		}
+		return current, nil
+	})
+}
+
+// SetTorrentsTrackerTiers changes the tier of the trackers of each of the specified torrents, indexed by URL;
+// a torrent error is returned for torrents which do not have one of the trackers.
+func (c *Client) SetTorrentsTrackerTiers(ids []string, tiers map[string]int64) ([]TorrentError, error) {
+	return c.updateTorrentsTrackers(ids, func(current []Tracker) ([]Tracker, error) {
+		for url, tier := range tiers {
+			i := trackerIndex(current, url)
+			if i == -1 {
+				return nil, fmt.Errorf("tracker %q not found", url)
+			}
+			current[i].Tier = tier
+		}
+		return current, nil
+	})
+}
+
+// SetTorrentTracker sets the primary tracker for the torrent with the
+// given hash to be `trackerURL`; the other trackers are kept, moved to the following tiers.
+func (c *Client) SetTorrentTracker(id, trackerURL string) error {
+	current, err := c.GetTorrentsTrackers([]string{id})
+	if err != nil {
+		return err
+	}
+	if _, ok := current[id]; !ok {
+		return ErrTorrentNotFound
+	}
+
+	trackers := []Tracker{{URL: trackerURL, Tier: 0}}
+	for _, t := range current[id] {
+		if t.URL == trackerURL {
+			continue
+		}
+		t.Tier++
+		trackers = append(trackers, t)
+	}
+
+	return c.setTorrentTrackers(id, trackers)
+}
+
+// updateTorrentsTrackers retrieves the trackers of the torrents and sets the trackers returned by update;
+// torrents which are not found or for which update fails are returned as torrent errors.
+func (c *Client) updateTorrentsTrackers(ids []string, update func(current []Tracker) ([]Tracker, error)) ([]TorrentError, error) {
+	current, err := c.GetTorrentsTrackers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var torrentErrors []TorrentError
+	for _, id := range ids {
+		trackers, ok := current[id]
+		if !ok {
+			torrentErrors = append(torrentErrors, TorrentError{ID: id, Message: ErrTorrentNotFound.Error()})
+			continue
+		}
+
+		trackers, err = update(trackers)
+		if err != nil {
+			torrentErrors = append(torrentErrors, TorrentError{ID: id, Message: err.Error()})
+			continue
+		}
+
+		setErrors, err := c.SetTorrentsTrackers([]string{id}, trackers)
+		if err != nil {
+			return torrentErrors, err
+		}
+		torrentErrors = append(torrentErrors, setErrors...)
+	}
+
+	return torrentErrors, nil
+}
+
+func (c *Client) setTorrentTrackers(id string, trackers []Tracker) error {
+	sorted := make([]Tracker, len(trackers))
+	copy(sorted, trackers)
+	sortTrackers(sorted)
+
+	var list rencode.List
+	for _, t := range sorted {
+		var tracker rencode.Dictionary
+		tracker.Add("url", t.URL)
+		tracker.Add("tier", t.Tier)
+		list.Add(tracker)
+	}
+
+	var args rencode.List
+	args.Add(id, list)
+
+	resp, err := c.rpc("core.set_torrent_trackers", args, rencode.Dictionary{})
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return resp.RPCError
+	}
+
+	return nil
+}
+
+// sortTrackers sorts the trackers by tier, keeping the order of trackers in the same tier.
+func sortTrackers(trackers []Tracker) {
+	sort.SliceStable(trackers, func(i, j int) bool { return trackers[i].Tier < trackers[j].Tier })
+}
+
+func trackerIndex(trackers []Tracker, url string) int {
+	for i, t := range trackers {
+		if t.URL == url {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,149 @@
+// go-libdeluge v0.5.6 - a native deluge RPC client library
+// Copyright (C) 2015~2023 gdm85 - https://github.com/gdm85/go-libdeluge/
+// This program is free software; you can redistribute it and/or
+// modify it under the terms of the GNU General Public License
+// as published by the Free Software Foundation; either version 2
+// of the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA  02110-1301, USA.
+
+package delugeclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdm85/go-rencode"
+)
+
+// sentTrackers returns the trackers sent with the i-th request, which must be set_torrent_trackers.
+func sentTrackers(t *testing.T, conn *mockConn, i int) (string, []Tracker) {
+	t.Helper()
+
+	var (
+		id   string
+		list rencode.List
+	)
+	method, args, _ := conn.requestAt(i)
+	if method != "core.set_torrent_trackers" {
+		t.Fatalf("unexpected method %q", method)
+	}
+	err := args.Scan(&id, &list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trackers []Tracker
+	for _, v := range list.Values() {
+		d := v.(rencode.Dictionary)
+		m, err := d.Zip()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tracker Tracker
+		err = decodeStruct(m, &tracker, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackers = append(trackers, tracker)
+	}
+
+	return id, trackers
+}
+
+func TestGetTorrentsTrackers(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(testStatusHash, testStatusDictionary("trackers", []Tracker{
+		{URL: "udp://b.example.org", Tier: 1},
+		{URL: "udp://a.example.org", Tier: 0},
+	})))
+
+	trackers, err := c.GetTorrentsTrackers([]string{testStatusHash, testStatusHash2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]Tracker{testStatusHash: {
+		{URL: "udp://a.example.org", Tier: 0},
+		{URL: "udp://b.example.org", Tier: 1},
+	}}
+	if !reflect.DeepEqual(trackers, expected) {
+		t.Errorf("expected %v, got %v", expected, trackers)
+	}
+}
+
+func TestAddTorrentsTrackers(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(testStatusHash, testStatusDictionary("trackers", []Tracker{
+		{URL: "udp://a.example.org", Tier: 0},
+	})))
+	conn.addResponse(2, nil)
+
+	torrentErrors, err := c.AddTorrentsTrackers([]string{testStatusHash, testStatusHash2}, []Tracker{{URL: "udp://b.example.org", Tier: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(torrentErrors, []TorrentError{{ID: testStatusHash2, Message: "torrent not found"}}) {
+		t.Errorf("unexpected torrent errors %v", torrentErrors)
+	}
+
+	requests := conn.sentRequests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	id, trackers := sentTrackers(t, conn, 1)
+	expected := []Tracker{{URL: "udp://a.example.org", Tier: 0}, {URL: "udp://b.example.org", Tier: 1}}
+	if id != testStatusHash || !reflect.DeepEqual(trackers, expected) {
+		t.Errorf("unexpected trackers for %s: %v", id, trackers)
+	}
+}
+
+func TestRemoveTorrentsTrackersNotFound(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(testStatusHash, testStatusDictionary("trackers", []Tracker{
+		{URL: "udp://a.example.org", Tier: 0},
+	})))
+
+	torrentErrors, err := c.RemoveTorrentsTrackers([]string{testStatusHash}, []string{"udp://b.example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(torrentErrors) != 1 {
+		t.Errorf("expected a torrent error, got %v", torrentErrors)
+	}
+	if len(conn.sentRequests()) != 1 {
+		t.Error("expected trackers not to be changed")
+	}
+}
+
+func TestSetTorrentTrackerKeepsTrackers(t *testing.T) {
+	t.Parallel()
+
+	c, conn := newMockConnClientV2(0)
+	conn.addResponse(1, testStatusDictionary(testStatusHash, testStatusDictionary("trackers", []Tracker{
+		{URL: "udp://a.example.org", Tier: 0},
+		{URL: "udp://b.example.org", Tier: 1},
+	})))
+	conn.addResponse(2, nil)
+
+	err := c.SetTorrentTracker(testStatusHash, "udp://b.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, trackers := sentTrackers(t, conn, 1)
+	expected := []Tracker{{URL: "udp://b.example.org", Tier: 0}, {URL: "udp://a.example.org", Tier: 1}}
+	if !reflect.DeepEqual(trackers, expected) {
+		t.Errorf("expected %v, got %v", expected, trackers)
+	}
+}